@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"github.com/form3tech-oss/go-ci-fuzz/fuzz"
+	"github.com/spf13/cobra"
+	"os"
+	"path/filepath"
+)
+
+const (
+	flagJSON = "json"
+)
+
+var corpusCmd = &cobra.Command{
+	Use:   "corpus",
+	Short: "Inspects and manages corpora of fuzz targets",
+	Long: `Inspects and manages corpora of fuzz targets.
+
+Corpora are stored the same way as 'go test' does it, i.e. in testdata/fuzz/<FuzzTarget> directory of each package.`,
+}
+
+func init() {
+	corpusCmd.AddCommand(corpusShowCmd)
+	corpusCmd.AddCommand(corpusImportCmd)
+	corpusCmd.AddCommand(corpusExportCmd)
+	corpusCmd.AddCommand(corpusCheckCmd)
+	corpusCmd.AddCommand(corpusPruneCmd)
+	corpusCmd.AddCommand(corpusExtractCmd)
+	corpusCmd.AddCommand(corpusMergeCmd)
+	corpusCmd.AddCommand(corpusReplaceCmd)
+	corpusCmd.AddCommand(corpusDiffCmd)
+	corpusCmd.AddCommand(corpusStatsCmd)
+}
+
+func newProject(cmd *cobra.Command) (*fuzz.Project, error) {
+	quiet, err := cmd.Flags().GetBool(flagQuiet)
+	if err != nil {
+		return nil, err
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	build, err := buildOptions(cmd, wd)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fuzz.Project{
+		Directory: wd,
+		Quiet:     quiet,
+		Build:     build,
+	}, nil
+}
+
+// buildOptions reads the build options of the config file and overrides them with the flags set.
+func buildOptions(cmd *cobra.Command, wd string) (fuzz.BuildOptions, error) {
+	path, err := cmd.Flags().GetString(flagConfig)
+	if err != nil {
+		return fuzz.BuildOptions{}, err
+	}
+
+	var config fuzz.Config
+	if path == "" {
+		path = filepath.Join(wd, fuzz.ConfigFile)
+		if _, err := os.Stat(path); err != nil {
+			path = ""
+		}
+	}
+	if path != "" {
+		if config, err = fuzz.LoadConfig(path); err != nil {
+			return fuzz.BuildOptions{}, err
+		}
+	}
+
+	build := config.Build
+	flags := cmd.Flags()
+	if flags.Changed(flagTags) {
+		if build.Tags, err = flags.GetStringSlice(flagTags); err != nil {
+			return fuzz.BuildOptions{}, err
+		}
+	}
+	if flags.Changed(flagLdflags) {
+		if build.Ldflags, err = flags.GetString(flagLdflags); err != nil {
+			return fuzz.BuildOptions{}, err
+		}
+	}
+	if flags.Changed(flagGcflags) {
+		if build.Gcflags, err = flags.GetString(flagGcflags); err != nil {
+			return fuzz.BuildOptions{}, err
+		}
+	}
+	if flags.Changed(flagMod) {
+		if build.Mod, err = flags.GetString(flagMod); err != nil {
+			return fuzz.BuildOptions{}, err
+		}
+	}
+	// only fuzz and serve define --race
+	if flags.Lookup(flagRace) != nil && flags.Changed(flagRace) {
+		if build.Race, err = flags.GetBool(flagRace); err != nil {
+			return fuzz.BuildOptions{}, err
+		}
+	}
+	env, err := flags.GetStringArray(flagEnv)
+	if err != nil {
+		return fuzz.BuildOptions{}, err
+	}
+	build.Env = append(build.Env, env...)
+	return build, build.Validate()
+}
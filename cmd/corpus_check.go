@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagFix        = "fix"
+	flagQuarantine = "quarantine"
+)
+
+var corpusCheckCmd = &cobra.Command{
+	Use:   "check [packages...]",
+	Short: "Validates corpus entries against fuzz target signatures",
+	Long: `Decodes every corpus entry of fuzz targets in [packages...] and compares the number and types of its values
+with the arguments of the f.Fuzz function of the target.
+
+Mismatched entries are reported and the command fails, unless --fix is defined in which case they are removed
+or moved to the --quarantine directory preserving their path.`,
+	Example:      `go-ci-fuzz corpus check ./... --fix --quarantine /tmp/quarantine`,
+	RunE:         corpusCheckRun,
+	SilenceUsage: true,
+}
+
+func init() {
+	corpusCheckCmd.Flags().Bool(flagFix, false, "remove mismatched entries")
+	corpusCheckCmd.Flags().String(flagQuarantine, "", "directory to move mismatched entries to instead of removing them, implies --fix")
+}
+
+func corpusCheckRun(cmd *cobra.Command, args []string) error {
+	fix, err := cmd.Flags().GetBool(flagFix)
+	if err != nil {
+		return err
+	}
+
+	quarantine, err := cmd.Flags().GetString(flagQuarantine)
+	if err != nil {
+		return err
+	}
+
+	proj, err := newProject(cmd)
+	if err != nil {
+		return err
+	}
+
+	packages := []string{"."}
+	if len(args) > 0 {
+		packages = args
+	}
+
+	targets, err := proj.ListFuzzTargets(cmd.Context(), packages...)
+	if err != nil {
+		return err
+	}
+	for _, target := range targets {
+		for _, warning := range target.Warnings {
+			cmd.Printf("go-ci-fuzz: skipping %s: %s\n", target, warning)
+		}
+	}
+
+	issues, err := proj.CorpusCheck(cmd.Context(), targets)
+	if err != nil {
+		return err
+	}
+
+	for _, issue := range issues {
+		cmd.Println(issue)
+	}
+
+	if len(issues) == 0 {
+		return nil
+	}
+
+	if !fix && quarantine == "" {
+		return fmt.Errorf("found %d mismatched corpus entries", len(issues))
+	}
+
+	if err := proj.CorpusQuarantine(issues, quarantine); err != nil {
+		return err
+	}
+
+	if quarantine != "" {
+		cmd.Printf("go-ci-fuzz: moved %d mismatched corpus entries to %s\n", len(issues), quarantine)
+	} else {
+		cmd.Printf("go-ci-fuzz: removed %d mismatched corpus entries\n", len(issues))
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/form3tech-oss/go-ci-fuzz/fuzz"
+	"github.com/form3tech-oss/go-ci-fuzz/fuzz/corpusfile"
+	"github.com/spf13/cobra"
+	"io"
+)
+
+var corpusDiffCmd = &cobra.Command{
+	Use:   "diff <a> <b> [packages...]",
+	Short: "Compares corpora of fuzz targets in two corpus locations",
+	Long: `Compares corpora of all fuzz targets in [packages...] stored in corpus locations <a> and <b> and lists entries
+only in <a>, only in <b> and entries whose content differs, along with their decoded values.
+
+Use . to compare with the corpora of the current directory.
+` + corpusLocations,
+	Example: `go-ci-fuzz corpus diff corpus.tar.zst . ./...
+go-ci-fuzz corpus diff s3://bucket/main s3://bucket/feature --json`,
+	Args:         cobra.MinimumNArgs(2),
+	RunE:         corpusDiffRun,
+	SilenceUsage: true,
+}
+
+func init() {
+	corpusDiffCmd.Flags().Bool(flagJSON, false, "print differences as JSON")
+}
+
+type corpusDiffTarget struct {
+	Name      string             `json:"name"`
+	Package   string             `json:"package"`
+	OnlyInA   []corpusEntry      `json:"only_in_a"`
+	OnlyInB   []corpusEntry      `json:"only_in_b"`
+	Differing []corpusDiffChange `json:"differing"`
+}
+
+type corpusDiffChange struct {
+	File string      `json:"file"`
+	A    corpusEntry `json:"a"`
+	B    corpusEntry `json:"b"`
+}
+
+func corpusDiffRun(cmd *cobra.Command, args []string) error {
+	asJSON, err := cmd.Flags().GetBool(flagJSON)
+	if err != nil {
+		return err
+	}
+
+	packages := []string{"."}
+	if len(args) > 2 {
+		packages = args[2:]
+	}
+
+	var results []fuzz.CorpusDiffResult
+	err = withCorpusStore(cmd, args[0], func(proj *fuzz.Project, a fuzz.CorpusStore) error {
+		return withCorpusStore(cmd, args[1], func(_ *fuzz.Project, b fuzz.CorpusStore) error {
+			results, err = proj.CorpusDiff(cmd.Context(), a, b, packages...)
+			return err
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	diffs := make([]corpusDiffTarget, 0, len(results))
+	for _, result := range results {
+		diff := corpusDiffTarget{
+			Name:      result.Target.Name,
+			Package:   result.Target.Package,
+			OnlyInA:   []corpusEntry{},
+			OnlyInB:   []corpusEntry{},
+			Differing: []corpusDiffChange{},
+		}
+		for _, entry := range result.OnlyInA {
+			diff.OnlyInA = append(diff.OnlyInA, decodeCorpusEntry(entry.Name, entry.A))
+		}
+		for _, entry := range result.OnlyInB {
+			diff.OnlyInB = append(diff.OnlyInB, decodeCorpusEntry(entry.Name, entry.B))
+		}
+		for _, entry := range result.Differing {
+			diff.Differing = append(diff.Differing, corpusDiffChange{
+				File: entry.Name,
+				A:    decodeCorpusEntry(entry.Name, entry.A),
+				B:    decodeCorpusEntry(entry.Name, entry.B),
+			})
+		}
+		diffs = append(diffs, diff)
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(diffs)
+	}
+
+	differences := 0
+	for i, result := range results {
+		if result.Empty() {
+			continue
+		}
+		differences++
+		printCorpusDiff(cmd.OutOrStdout(), result.Target, diffs[i])
+	}
+	if differences == 0 {
+		cmd.Println("go-ci-fuzz: corpora are identical")
+	}
+	return nil
+}
+
+func decodeCorpusEntry(file string, data []byte) corpusEntry {
+	entry := corpusEntry{File: file}
+	vals, err := corpusfile.Unmarshal(data)
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	for _, val := range vals {
+		entry.Values = append(entry.Values, corpusfile.Describe(val))
+	}
+	return entry
+}
+
+func printCorpusDiff(w io.Writer, target fuzz.Target, diff corpusDiffTarget) {
+	fmt.Fprintln(w, target)
+	for _, entry := range diff.OnlyInA {
+		fmt.Fprintf(w, "- %s\n", entry.File)
+		printDiffValues(w, "    ", entry)
+	}
+	for _, entry := range diff.OnlyInB {
+		fmt.Fprintf(w, "+ %s\n", entry.File)
+		printDiffValues(w, "    ", entry)
+	}
+	for _, change := range diff.Differing {
+		fmt.Fprintf(w, "~ %s\n", change.File)
+		fmt.Fprintln(w, "  a:")
+		printDiffValues(w, "    ", change.A)
+		fmt.Fprintln(w, "  b:")
+		printDiffValues(w, "    ", change.B)
+	}
+	fmt.Fprintln(w)
+}
+
+func printDiffValues(w io.Writer, indent string, entry corpusEntry) {
+	if entry.Error != "" {
+		fmt.Fprintf(w, "%serror: %s\n", indent, entry.Error)
+	}
+	printCorpusValues(w, indent, entry.Values)
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"github.com/form3tech-oss/go-ci-fuzz/fuzz"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagRaw = "raw"
+)
+
+var corpusExportCmd = &cobra.Command{
+	Use:   "export <destination> [packages...]",
+	Short: "Exports corpora of fuzz targets as raw files",
+	Long: `Exports corpora of all fuzz targets in [packages...] to <destination> as raw files for other tools, e.g. hex
+editors or other fuzzers, see 'corpus extract' for supported locations and for exporting the 'go test fuzz v1' format.
+The structure is identical to how corpora is stored locally, e.g.
+destination
+└── testdata
+    └── fuzz
+        └── FuzzTarget
+            └── 0a7e5e215d8c088d
+
+Entries with a single []byte or string value are written as raw binary files. All other entries, including entries
+with a single value of another type, e.g. int or rune, are written as JSON documents named <entry>.json.
+Entries which cannot be decoded are skipped with a warning.
+`,
+	Example:      `go-ci-fuzz corpus export /tmp/corpus ./...`,
+	Args:         cobra.MinimumNArgs(1),
+	RunE:         corpusExportRun,
+	SilenceUsage: true,
+}
+
+func init() {
+	// entries are always exported as raw files, the flag is kept for compatibility
+	corpusExportCmd.Flags().Bool(flagRaw, true, "write entries as raw files")
+	_ = corpusExportCmd.Flags().MarkHidden(flagRaw)
+}
+
+func corpusExportRun(cmd *cobra.Command, args []string) error {
+	location, packages := corpusStoreArgs(args)
+	return withCorpusStore(cmd, location, func(proj *fuzz.Project, store fuzz.CorpusStore) error {
+		result, err := proj.CorpusExportRaw(cmd.Context(), store, packages...)
+		for _, skipped := range result.Skipped {
+			cmd.Printf("go-ci-fuzz: warning: %s\n", skipped)
+		}
+		if err != nil {
+			return err
+		}
+		cmd.Printf("go-ci-fuzz: exported %d entries to %s, skipped %d\n", result.Exported, location, len(result.Skipped))
+		return nil
+	})
+}
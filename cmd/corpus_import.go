@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagTarget = "target"
+)
+
+var corpusImportCmd = &cobra.Command{
+	Use:   "import --target <package>#<FuzzTarget> <dir>...",
+	Short: "Imports raw files as corpus entries",
+	Long: `Converts raw files, e.g. go-fuzz or libFuzzer corpora, into 'go test fuzz v1' corpus entries of --target.
+
+Only targets accepting a single []byte or string argument are supported. Entries are named by their content hash
+like 'go test' does, so duplicates and entries which are already present are skipped. Hidden files and directories,
+e.g. .DS_Store, the *.output and *.quoted files written by go-fuzz next to crashers and the go-fuzz suppressions
+directory are ignored.`,
+	Example:      `go-ci-fuzz corpus import --target ./parser#FuzzParse ~/go-fuzz/parser/corpus`,
+	Args:         cobra.MinimumNArgs(1),
+	RunE:         corpusImportRun,
+	SilenceUsage: true,
+}
+
+func init() {
+	corpusImportCmd.Flags().String(flagTarget, "", "target to import entries into, e.g. ./parser#FuzzParse")
+	_ = corpusImportCmd.MarkFlagRequired(flagTarget)
+}
+
+func corpusImportRun(cmd *cobra.Command, args []string) error {
+	spec, err := cmd.Flags().GetString(flagTarget)
+	if err != nil {
+		return err
+	}
+
+	proj, err := newProject(cmd)
+	if err != nil {
+		return err
+	}
+
+	target, err := proj.FindFuzzTarget(cmd.Context(), spec)
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range args {
+		result, err := proj.CorpusImport(cmd.Context(), target, dir)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("go-ci-fuzz: imported %d entries from %s into %s, skipped %d duplicates\n", result.Imported, dir, target, result.Skipped)
+	}
+	return nil
+}
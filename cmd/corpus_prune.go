@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagDelete = "delete"
+	flagRename = "rename"
+)
+
+var corpusPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Finds corpora of fuzz targets which no longer exist",
+	Long: `Walks every testdata/fuzz/<FuzzTarget> directory in the module and lists those without a matching fuzz target,
+e.g. after the target has been renamed or deleted. Targets are looked up in all test files regardless of build
+constraints, so corpora of targets behind build tags or of other platforms are kept.
+
+Orphaned corpora are deleted with --delete. Use --rename to move the corpus of a renamed target to its new name instead,
+the new target must be in the same package.`,
+	Example: `go-ci-fuzz corpus prune
+go-ci-fuzz corpus prune --rename FuzzOldName=FuzzNewName --delete`,
+	Args:         cobra.NoArgs,
+	RunE:         corpusPruneRun,
+	SilenceUsage: true,
+}
+
+func init() {
+	corpusPruneCmd.Flags().Bool(flagDelete, false, "delete orphaned corpora")
+	corpusPruneCmd.Flags().StringToString(flagRename, nil, "move corpora of renamed targets, e.g. FuzzOldName=FuzzNewName")
+}
+
+func corpusPruneRun(cmd *cobra.Command, args []string) error {
+	del, err := cmd.Flags().GetBool(flagDelete)
+	if err != nil {
+		return err
+	}
+
+	renames, err := cmd.Flags().GetStringToString(flagRename)
+	if err != nil {
+		return err
+	}
+
+	proj, err := newProject(cmd)
+	if err != nil {
+		return err
+	}
+
+	orphans, err := proj.OrphanedCorpora(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	moved, err := proj.CorpusRename(cmd.Context(), orphans, renames)
+	if err != nil {
+		return err
+	}
+
+	var remaining []string
+	for _, orphan := range orphans {
+		if dest, ok := moved[orphan]; ok {
+			cmd.Printf("go-ci-fuzz: moved %s to %s\n", orphan, dest)
+			continue
+		}
+		remaining = append(remaining, orphan)
+	}
+
+	if !del {
+		for _, orphan := range remaining {
+			cmd.Println(orphan)
+		}
+		return nil
+	}
+
+	if err := proj.CorpusPrune(remaining); err != nil {
+		return err
+	}
+	for _, orphan := range remaining {
+		cmd.Printf("go-ci-fuzz: deleted %s\n", orphan)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/form3tech-oss/go-ci-fuzz/fuzz/corpusfile"
+	"github.com/spf13/cobra"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var corpusShowCmd = &cobra.Command{
+	Use:   "show <file|dir|FuzzTarget/id> [packages...]",
+	Short: "Pretty-prints corpus entries",
+	Long: `Decodes corpus entries and prints each value with its type.
+Byte slices are printed as a hex dump, strings and byte slices are printed with their length.
+
+The argument is either a corpus file, a directory of corpus files or an entry ID as printed by 'go test', e.g.
+FuzzTarget/0a7e5e215d8c088d. IDs are resolved against the fuzz targets discovered in [packages...], all packages by default.
+`,
+	Example: `go-ci-fuzz corpus show testdata/fuzz/FuzzTarget
+go-ci-fuzz corpus show FuzzTarget/0a7e5e215d8c088d --json`,
+	Args:         cobra.MinimumNArgs(1),
+	RunE:         corpusShowRun,
+	SilenceUsage: true,
+}
+
+func init() {
+	corpusShowCmd.Flags().Bool(flagJSON, false, "print entries as JSON")
+}
+
+type corpusEntry struct {
+	File   string             `json:"file"`
+	Values []corpusfile.Value `json:"values,omitempty"`
+	Error  string             `json:"error,omitempty"`
+}
+
+func corpusShowRun(cmd *cobra.Command, args []string) error {
+	asJSON, err := cmd.Flags().GetBool(flagJSON)
+	if err != nil {
+		return err
+	}
+
+	files, err := resolveCorpusFiles(cmd, args[0], args[1:])
+	if err != nil {
+		return err
+	}
+
+	var entries []corpusEntry
+	failed := 0
+	for _, file := range files {
+		entry := corpusEntry{File: file}
+		vals, err := corpusfile.ReadFile(file)
+		if err != nil {
+			entry.Error = err.Error()
+			failed++
+		}
+		for _, val := range vals {
+			entry.Values = append(entry.Values, corpusfile.Describe(val))
+		}
+		entries = append(entries, entry)
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(entries); err != nil {
+			return err
+		}
+	} else {
+		for _, entry := range entries {
+			printCorpusEntry(cmd.OutOrStdout(), entry)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d corpus entries could not be decoded", failed, len(entries))
+	}
+	return nil
+}
+
+// resolveCorpusFiles returns the corpus files referenced by arg which is either a file,
+// a directory or FuzzTarget/id of an entry belonging to one of the targets in packages.
+func resolveCorpusFiles(cmd *cobra.Command, arg string, packages []string) ([]string, error) {
+	info, err := os.Stat(arg)
+	if err == nil && !info.IsDir() {
+		return []string{arg}, nil
+	}
+
+	if err == nil {
+		var files []string
+		err := filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.Type().IsRegular() {
+				files = append(files, path)
+			}
+			return nil
+		})
+		return files, err
+	}
+
+	if !os.IsNotExist(err) || !strings.HasPrefix(arg, "Fuzz") {
+		return nil, err
+	}
+
+	proj, err := newProject(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(packages) == 0 {
+		packages = []string{"..."}
+	}
+
+	paths, err := proj.FindCorpusEntry(cmd.Context(), arg, packages...)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, len(paths))
+	for i, path := range paths {
+		files[i], err = filepath.Rel(proj.Directory, path)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func printCorpusEntry(w io.Writer, entry corpusEntry) {
+	fmt.Fprintln(w, entry.File)
+	if entry.Error != "" {
+		fmt.Fprintf(w, "  error: %s\n", entry.Error)
+	}
+
+	printCorpusValues(w, "  ", entry.Values)
+	fmt.Fprintln(w)
+}
+
+// printCorpusValues prints decoded values of a corpus entry, each line prefixed with indent.
+func printCorpusValues(w io.Writer, indent string, values []corpusfile.Value) {
+	for i, val := range values {
+		fmt.Fprintf(w, "%s[%d] %s", indent, i, val.Type)
+		if val.Length != nil {
+			fmt.Fprintf(w, " (len %d)", *val.Length)
+		}
+
+		// NaN and ±Inf are kept as strings by Describe, they're printed the same way as in Go source instead
+		if expr, ok := corpusfile.NonFinite(val); ok {
+			fmt.Fprintf(w, ": %s\n", expr)
+			continue
+		}
+
+		switch v := val.Value.(type) {
+		case []byte:
+			fmt.Fprintln(w, ":")
+			for _, line := range strings.Split(strings.TrimSuffix(hex.Dump(v), "\n"), "\n") {
+				if line != "" {
+					fmt.Fprintf(w, "%s    %s\n", indent, line)
+				}
+			}
+		case string:
+			fmt.Fprintf(w, ": %q\n", v)
+		case uint8:
+			fmt.Fprintf(w, ": %d %q\n", v, v)
+		case int32:
+			fmt.Fprintf(w, ": %d %q\n", v, v)
+		default:
+			fmt.Fprintf(w, ": %v\n", v)
+		}
+	}
+}
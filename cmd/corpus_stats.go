@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/cobra"
+	"text/tabwriter"
+	"time"
+)
+
+var corpusStatsCmd = &cobra.Command{
+	Use:   "stats [packages...]",
+	Short: "Prints corpus statistics of fuzz targets",
+	Long: `Prints statistics of corpora of fuzz targets in [packages...]: the number of seed files in testdata/fuzz,
+the number of f.Add calls, the number of entries generated by the fuzzing engine in $GOCACHE/fuzz,
+total, mean and maximum entry size and the age of the newest entry.
+
+Sizes and ages cover both seed files and generated entries, they help to spot corpora which stagnated or exploded.`,
+	Example:      `go-ci-fuzz corpus stats ./... --json`,
+	RunE:         corpusStatsRun,
+	SilenceUsage: true,
+}
+
+func init() {
+	corpusStatsCmd.Flags().Bool(flagJSON, false, "print statistics as JSON")
+}
+
+type corpusStats struct {
+	Name         string     `json:"name"`
+	Package      string     `json:"package"`
+	SeedFiles    int        `json:"seed_files"`
+	SeedCalls    int        `json:"seed_calls"`
+	CacheEntries int        `json:"cache_entries"`
+	TotalSize    int64      `json:"total_size"`
+	MeanSize     int64      `json:"mean_size"`
+	MaxSize      int64      `json:"max_size"`
+	Newest       *time.Time `json:"newest,omitempty"`
+	NewestAge    float64    `json:"newest_age_seconds,omitempty"`
+}
+
+func corpusStatsRun(cmd *cobra.Command, args []string) error {
+	asJSON, err := cmd.Flags().GetBool(flagJSON)
+	if err != nil {
+		return err
+	}
+
+	proj, err := newProject(cmd)
+	if err != nil {
+		return err
+	}
+
+	packages := []string{"."}
+	if len(args) > 0 {
+		packages = args
+	}
+
+	stats, err := proj.CorpusStats(cmd.Context(), packages...)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if asJSON {
+		listed := make([]corpusStats, 0, len(stats))
+		for _, s := range stats {
+			entry := corpusStats{
+				Name:         s.Target.Name,
+				Package:      s.Target.Package,
+				SeedFiles:    s.SeedFiles,
+				SeedCalls:    s.SeedCalls,
+				CacheEntries: s.CacheEntries,
+				TotalSize:    s.TotalSize,
+				MeanSize:     s.MeanSize(),
+				MaxSize:      s.MaxSize,
+			}
+			if !s.Newest.IsZero() {
+				newest := s.Newest
+				entry.Newest = &newest
+				entry.NewestAge = now.Sub(newest).Seconds()
+			}
+			listed = append(listed, entry)
+		}
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(listed)
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TARGET\tSEED FILES\tF.ADD\tCACHE\tTOTAL\tMEAN\tMAX\tNEWEST")
+	for _, s := range stats {
+		age := "-"
+		if !s.Newest.IsZero() {
+			age = formatAge(now.Sub(s.Newest)) + " ago"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%d\t%s\n", s.Target, s.SeedFiles, s.SeedCalls, s.CacheEntries, s.TotalSize, s.MeanSize(), s.MaxSize, age)
+	}
+	return w.Flush()
+}
+
+// formatAge formats d with a precision suited for corpus entries, i.e. days for old entries and seconds for recent ones.
+func formatAge(d time.Duration) string {
+	if d >= 48*time.Hour {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return d.Round(time.Second).String()
+}
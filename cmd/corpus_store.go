@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/form3tech-oss/go-ci-fuzz/fuzz"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagArchive        = "archive"
+	flagFailOnConflict = "fail-on-conflict"
+)
+
+const corpusLocations = `Corpus locations are either
+- a local directory, as a plain path or file:///path
+- a tar archive, optionally compressed with gzip or zstd, e.g. corpus.tar.gz or corpus.tar.zst.
+  Archives contain a manifest.json listing entry counts and checksums of every target which are verified when reading them.
+- an S3 compatible object store, e.g. s3://bucket/prefix.
+  Credentials are taken from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN, the region from AWS_REGION.
+  Use AWS_ENDPOINT_URL_S3 or s3://bucket/prefix?endpoint=http://localhost:9000 for other object stores, e.g. MinIO.
+`
+
+var corpusExtractCmd = &cobra.Command{
+	Use:   "extract <destination>|--archive <archive> [packages...]",
+	Short: "Copies corpora of fuzz targets to a corpus location",
+	Long: `Copies corpora of all fuzz targets in [packages...] to <destination>.
+The structure is identical to how corpora is stored locally, i.e. <package>/testdata/fuzz/<FuzzTarget>/<entry>.
+
+` + corpusLocations,
+	Example: `go-ci-fuzz corpus extract s3://corpora/my-repo ./...
+go-ci-fuzz corpus extract --archive corpus.tar.zst ./...`,
+	RunE:         corpusExtractRun,
+	SilenceUsage: true,
+}
+
+var corpusMergeCmd = &cobra.Command{
+	Use:   "merge <source>|--archive <archive> [packages...]",
+	Short: "Copies corpora of fuzz targets from a corpus location",
+	Long: `Copies corpora of all fuzz targets in [packages...] from <source> into the current directory.
+
+Entries are content addressed: identical entries are skipped and entries with the same name but different content
+are reported as conflicts and left untouched. The command fails on conflicts with --fail-on-conflict.
+
+` + corpusLocations,
+	Example: `go-ci-fuzz corpus merge s3://corpora/my-repo ./...
+go-ci-fuzz corpus merge --archive corpus.tar.zst ./...`,
+	RunE:         corpusMergeRun,
+	SilenceUsage: true,
+}
+
+var corpusReplaceCmd = &cobra.Command{
+	Use:   "replace <source> [packages...]",
+	Short: "Replaces corpora of fuzz targets with the ones from a corpus location",
+	Long: `Deletes corpora of all fuzz targets in [packages...] and copies the ones from <source> into the current directory.
+
+` + corpusLocations,
+	Example:      `go-ci-fuzz corpus replace corpus.tar.gz ./...`,
+	Args:         cobra.MinimumNArgs(1),
+	RunE:         corpusReplaceRun,
+	SilenceUsage: true,
+}
+
+func init() {
+	corpusExtractCmd.Flags().String(flagArchive, "", "archive to write corpora to instead of <destination>, e.g. corpus.tar.zst")
+	corpusMergeCmd.Flags().String(flagArchive, "", "archive to read corpora from instead of <source>, e.g. corpus.tar.zst")
+	corpusMergeCmd.Flags().Bool(flagFailOnConflict, false, "fail if an entry differs from an existing entry of the same name")
+}
+
+func corpusStoreArgs(args []string) (string, []string) {
+	packages := []string{"."}
+	if len(args) > 1 {
+		packages = args[1:]
+	}
+	return args[0], packages
+}
+
+// corpusLocationArgs returns the corpus location and packages from args unless --archive is defined,
+// in which case all args are packages.
+func corpusLocationArgs(cmd *cobra.Command, args []string) (string, []string, error) {
+	archive, err := cmd.Flags().GetString(flagArchive)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if archive == "" {
+		if len(args) == 0 {
+			return "", nil, errors.New("missing corpus location, pass it as the first argument or use --archive")
+		}
+		location, packages := corpusStoreArgs(args)
+		return location, packages, nil
+	}
+
+	if !fuzz.IsArchive(archive) {
+		return "", nil, errors.New("--archive must end with .tar, .tar.gz, .tgz, .tar.zst or .tzst")
+	}
+
+	packages := []string{"."}
+	if len(args) > 0 {
+		packages = args
+	}
+	return archive, packages, nil
+}
+
+// withCorpusStore opens the corpus store at location and closes it once fn finishes.
+func withCorpusStore(cmd *cobra.Command, location string, fn func(proj *fuzz.Project, store fuzz.CorpusStore) error) (err error) {
+	proj, err := newProject(cmd)
+	if err != nil {
+		return err
+	}
+
+	store, err := fuzz.OpenCorpusStore(cmd.Context(), location)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := store.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	return fn(proj, store)
+}
+
+func corpusExtractRun(cmd *cobra.Command, args []string) error {
+	location, packages, err := corpusLocationArgs(cmd, args)
+	if err != nil {
+		return err
+	}
+	return withCorpusStore(cmd, location, func(proj *fuzz.Project, store fuzz.CorpusStore) error {
+		return proj.CorpusExtractTo(cmd.Context(), store, packages...)
+	})
+}
+
+func corpusMergeRun(cmd *cobra.Command, args []string) error {
+	failOnConflict, err := cmd.Flags().GetBool(flagFailOnConflict)
+	if err != nil {
+		return err
+	}
+
+	location, packages, err := corpusLocationArgs(cmd, args)
+	if err != nil {
+		return err
+	}
+	return withCorpusStore(cmd, location, func(proj *fuzz.Project, store fuzz.CorpusStore) error {
+		if archive, ok := store.(*fuzz.ArchiveStore); ok && archive.Manifest != nil {
+			cmd.Printf("go-ci-fuzz: verified checksums of %d corpora in %s\n", len(archive.Manifest.Targets), archive.Path)
+		}
+		results, err := proj.CorpusMergeFrom(cmd.Context(), store, fuzz.MergeOptions{FailOnConflict: failOnConflict}, packages...)
+		printMergeResults(cmd, results)
+		return err
+	})
+}
+
+func corpusReplaceRun(cmd *cobra.Command, args []string) error {
+	location, packages := corpusStoreArgs(args)
+	return withCorpusStore(cmd, location, func(proj *fuzz.Project, store fuzz.CorpusStore) error {
+		results, err := proj.CorpusReplaceFrom(cmd.Context(), store, packages...)
+		printMergeResults(cmd, results)
+		return err
+	})
+}
+
+func printMergeResults(cmd *cobra.Command, results []fuzz.MergeResult) {
+	for _, result := range results {
+		cmd.Printf("go-ci-fuzz: %s: added %d, skipped %d identical, %d conflicting\n", result.Target, result.Added, result.Skipped, len(result.Conflicts))
+		for _, conflict := range result.Conflicts {
+			cmd.Printf("  conflict: %s\n", conflict)
+		}
+	}
+}
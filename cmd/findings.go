@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/form3tech-oss/go-ci-fuzz/fuzz"
+	"github.com/spf13/cobra"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	flagDB  = "db"
+	flagAll = "all"
+)
+
+var findingsCmd = &cobra.Command{
+	Use:   "findings",
+	Short: "Inspects and resolves recorded findings",
+	Long: `Inspects and resolves findings recorded by 'go-ci-fuzz fuzz --findings' and 'go-ci-fuzz serve'.
+
+Findings are deduplicated by a signature derived from the failure message and the innermost stack frames,
+so the same bug found by different inputs or at different commits is recorded once along with the number of times
+it was seen. Signatures may be abbreviated to a unique prefix.`,
+}
+
+var findingsListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "Lists open findings",
+	RunE:         findingsListRun,
+	SilenceUsage: true,
+}
+
+var findingsShowCmd = &cobra.Command{
+	Use:          "show <signature>",
+	Short:        "Prints a finding along with its history",
+	Args:         cobra.ExactArgs(1),
+	RunE:         findingsShowRun,
+	SilenceUsage: true,
+}
+
+var findingsResolveCmd = &cobra.Command{
+	Use:   "resolve <signature>...",
+	Short: "Marks findings as resolved",
+	Long: `Marks findings as resolved, e.g. once the bug is fixed. A resolved finding seen again is reopened and reported
+as regressed.`,
+	Args:         cobra.MinimumNArgs(1),
+	RunE:         findingsResolveRun,
+	SilenceUsage: true,
+}
+
+func init() {
+	findingsCmd.PersistentFlags().String(flagDB, "findings.jsonl", "findings database, e.g. findings.jsonl in --state-dir of 'go-ci-fuzz serve'")
+	findingsCmd.AddCommand(findingsListCmd)
+	findingsCmd.AddCommand(findingsShowCmd)
+	findingsCmd.AddCommand(findingsResolveCmd)
+
+	findingsListCmd.Flags().Bool(flagAll, false, "list resolved findings as well")
+	findingsListCmd.Flags().Bool(flagJSON, false, "print findings as JSON")
+	findingsShowCmd.Flags().Bool(flagJSON, false, "print the finding and its history as JSON")
+}
+
+type shownFinding struct {
+	fuzz.Finding
+	History []fuzz.Finding `json:"history"`
+}
+
+func openFindings(cmd *cobra.Command) (*fuzz.FindingStore, error) {
+	db, err := cmd.Flags().GetString(flagDB)
+	if err != nil {
+		return nil, err
+	}
+	return fuzz.OpenFindingStore(db)
+}
+
+func findingsListRun(cmd *cobra.Command, _ []string) error {
+	all, err := cmd.Flags().GetBool(flagAll)
+	if err != nil {
+		return err
+	}
+	asJSON, err := cmd.Flags().GetBool(flagJSON)
+	if err != nil {
+		return err
+	}
+
+	store, err := openFindings(cmd)
+	if err != nil {
+		return err
+	}
+
+	var findings []fuzz.Finding
+	for _, finding := range store.Findings() {
+		if all || finding.Resolved == nil {
+			findings = append(findings, finding)
+		}
+	}
+
+	if asJSON {
+		if findings == nil {
+			findings = []fuzz.Finding{}
+		}
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(findings)
+	}
+
+	now := time.Now()
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SIGNATURE\tKIND\tTARGET\tCOUNT\tFIRST SEEN\tLAST SEEN\tSTATUS")
+	for _, finding := range findings {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s ago\t%s ago\t%s\n", finding.Signature, finding.Kind, finding.Target, finding.Count,
+			formatAge(now.Sub(finding.FirstSeen)), formatAge(now.Sub(finding.LastSeen)), findingStatus(finding))
+	}
+	return w.Flush()
+}
+
+func findingsShowRun(cmd *cobra.Command, args []string) error {
+	asJSON, err := cmd.Flags().GetBool(flagJSON)
+	if err != nil {
+		return err
+	}
+
+	store, err := openFindings(cmd)
+	if err != nil {
+		return err
+	}
+	finding, err := store.Lookup(args[0])
+	if err != nil {
+		return err
+	}
+	history := store.History(finding.Signature)
+
+	if asJSON {
+		shown := shownFinding{Finding: finding, History: append([]fuzz.Finding{}, history...)}
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(shown)
+	}
+
+	cmd.Printf("signature:  %s\n", finding.Signature)
+	cmd.Printf("target:     %s\n", finding.Target)
+	cmd.Printf("kind:       %s\n", finding.Kind)
+	cmd.Printf("status:     %s\n", findingStatus(finding))
+	cmd.Printf("count:      %d\n", finding.Count)
+	cmd.Printf("first seen: %s\n", finding.FirstSeen.Format(time.RFC3339))
+	cmd.Printf("last seen:  %s\n", finding.LastSeen.Format(time.RFC3339))
+	if finding.Commit != "" {
+		cmd.Printf("commit:     %s\n", finding.Commit)
+	}
+	if finding.Input != "" {
+		cmd.Printf("input:      %s\n", finding.Input)
+	}
+	if finding.Output != "" {
+		cmd.Println("output:")
+		for _, line := range strings.Split(finding.Output, "\n") {
+			cmd.Printf("  %s\n", line)
+		}
+	}
+
+	cmd.Println("history:")
+	for _, entry := range history {
+		if entry.Resolved != nil {
+			cmd.Printf("  %s  resolved\n", entry.Resolved.Format(time.RFC3339))
+			continue
+		}
+		line := fmt.Sprintf("  %s  seen", entry.LastSeen.Format(time.RFC3339))
+		if entry.Commit != "" {
+			line += " at " + entry.Commit
+		}
+		if entry.Input != "" {
+			line += ", input " + entry.Input
+		}
+		cmd.Println(line)
+	}
+	return nil
+}
+
+func findingsResolveRun(cmd *cobra.Command, args []string) error {
+	store, err := openFindings(cmd)
+	if err != nil {
+		return err
+	}
+
+	for _, arg := range args {
+		finding, err := store.Lookup(arg)
+		if err != nil {
+			return err
+		}
+		if finding.Resolved != nil {
+			cmd.Printf("go-ci-fuzz: %s is already resolved\n", finding.Signature)
+			continue
+		}
+		if _, err := store.Resolve(finding.Signature, time.Now().UTC()); err != nil {
+			return err
+		}
+		cmd.Printf("go-ci-fuzz: resolved %s in %s\n", finding.Signature, finding.Target)
+	}
+	return nil
+}
+
+func findingStatus(finding fuzz.Finding) string {
+	if finding.Resolved != nil {
+		return "resolved"
+	}
+	return "open"
+}
@@ -1,137 +1,201 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"github.com/form3tech-oss/go-ci-fuzz/fuzz"
 	"github.com/spf13/cobra"
+	"net"
+	"net/http"
 	"os"
-	"path/filepath"
 	"time"
 )
 
 const (
-	flagFuzzTime = "fuzz-time"
-	flagFailFast = "fail-fast"
-	flagOut      = "out"
+	flagFuzzTime           = "fuzz-time"
+	flagFailFast           = "fail-fast"
+	flagOut                = "out"
+	flagDeadline           = "deadline"
+	flagFailOnEmpty        = "fail-on-empty"
+	flagReport             = "report"
+	flagMetricsAddr        = "metrics-addr"
+	flagFindings           = "findings"
+	flagSuppressions       = "suppressions"
+	flagContinueSuppressed = "continue-suppressed"
+	flagReproduce          = "reproduce"
+	flagDiscardNonRepro    = "discard-non-reproducible"
+	flagRace               = "race"
 )
 
 var fuzzCmd = &cobra.Command{
 	Use:   "fuzz [packages...]",
 	Short: "Runs all fuzz targets of packages",
-	Long: `Runs all fuzz targets in <packages> in current directory for the duration of --fuzz-time / N where N is the number of fuzz targets.
-Continues to the next fuzz target on failure unless --fail-fast is defined.
-
-Failing outputs are written to --out directory if specified. The structure is identical to how corpora is stored locally.
-e.g.
-out-dir
-└── testdata
-    └── fuzz
-        └── FuzzTarget
-            └── 0a7e5e215d8c088d4b9c4993d0189a07e81603fbdf64f2ca44738aa27159acef
+	Long: `Runs all fuzz targets in <packages> in current directory one after another. --fuzz-time is divided evenly among
+the targets. --deadline bounds the whole run instead, including discovery, compilation and re-runs of failing inputs:
+the time spent outside of fuzzing is measured and taken from the time slices of the remaining targets, and targets
+which don't fit before the deadline are skipped.
+
+Failing inputs are classified as crashes, hangs, out-of-memory kills or data races and reported along with the relevant
+excerpt of the output. They're copied to --out using the same structure as corpora in the project, e.g.
+<out>/testdata/fuzz/FuzzTarget/0a7e5e215d8c088d, and the run continues with the next target unless --fail-fast is
+defined. New failing inputs can be re-run to tell whether they're reproducible, recorded in a findings database
+deduplicated by their signature, see 'go-ci-fuzz findings', and matched against suppressions of known failures which
+don't affect the exit code.
+
+Targets are discovered and built with the build options of the config file, .go-ci-fuzz.json in current directory
+unless --config is defined, e.g.
+{"build": {"tags": ["integration"], "mod": "vendor", "race": true, "env": ["GOEXPERIMENT=arenas"]}}
+
+On SIGINT or SIGTERM the running target is stopped gracefully and failing inputs found so far are still written
+to --out, a second signal stops immediately.
+
+Exit codes:
+  0    no failing inputs found
+  1    tool error, e.g. discovery or build failure
+  2    new failing inputs found
+  3    only inputs of the seed corpus failed
+  4    no fuzz targets found and --fail-on-empty is defined
+  130  interrupted by SIGINT or SIGTERM
 `,
 	Run:          fuzzRun,
 	SilenceUsage: true,
 }
 
 func init() {
-	fuzzCmd.Flags().StringP(flagOut, "o", "", "directory to write failing outputs to")
+	fuzzCmd.Flags().StringP(flagOut, "o", "", "directory to copy failing inputs to")
 	fuzzCmd.Flags().Duration(flagFuzzTime, 10*time.Minute, "fuzzing duration for the whole suite")
 	fuzzCmd.Flags().Bool(flagFailFast, false, "exit once failing input is discovered")
+	fuzzCmd.Flags().Duration(flagDeadline, 0, "wall-clock time budget for the whole run including discovery and compilation, overrides --fuzz-time")
+	fuzzCmd.MarkFlagsMutuallyExclusive(flagFuzzTime, flagDeadline)
+	fuzzCmd.Flags().Bool(flagFailOnEmpty, false, "fail if no fuzz targets are found")
+	fuzzCmd.Flags().String(flagReport, "", "file to write a JSON report of the run to")
+	fuzzCmd.Flags().String(flagMetricsAddr, "", "address to serve Prometheus metrics of the run at, e.g. :9090 for http://localhost:9090/metrics")
+	fuzzCmd.Flags().String(flagFindings, "", "findings database to record failing inputs in, e.g. findings.jsonl, failures are reported as new, known or regressed")
+	fuzzCmd.Flags().String(flagSuppressions, "", `JSON file of known failures which don't affect the exit code, matched by target, signature or message regular expression, e.g. [{"target": "FuzzParse", "message": "index out of range", "reason": "#123"}]`)
+	fuzzCmd.Flags().Bool(flagContinueSuppressed, false, "continue fuzzing a target for the rest of its time slice after a suppressed failure, the failing input is removed from the corpus")
+	fuzzCmd.Flags().Bool(flagRace, false, "fuzz with the race detector enabled, data races are reported along with the race report")
+	fuzzCmd.Flags().Int(flagReproduce, 0, "number of times to re-run new failing inputs with 'go test -run' to tell whether they're reproducible, flaky or non-reproducible")
+	fuzzCmd.Flags().Bool(flagDiscardNonRepro, false, "remove failing inputs which failed in no re-run from the corpus, they don't affect the exit code, requires --reproduce")
 }
 
 func fuzzRun(cmd *cobra.Command, args []string) {
-	ctx := cmd.Context()
-
-	quiet, err := cmd.Flags().GetBool(flagQuiet)
+	result, report, err := runFuzz(cmd, args)
 	if err != nil {
-		cmd.PrintErrln(err)
-		os.Exit(1)
+		result.Err = err
+	}
+	if result.Err != nil && !result.Interrupted {
+		cmd.PrintErrln(result.Err)
 	}
 
-	fuzzTime, err := cmd.Flags().GetDuration(flagFuzzTime)
-	if err != nil {
-		cmd.PrintErrln(err)
-		os.Exit(1)
+	if report != "" {
+		if err := writeReport(report, result); err != nil {
+			cmd.PrintErrf("writing report to %s failed: %s\n", report, err)
+			os.Exit(fuzz.ExitError)
+		}
 	}
+	os.Exit(result.ExitCode())
+}
 
-	out, err := cmd.Flags().GetString(flagOut)
+func runFuzz(cmd *cobra.Command, args []string) (fuzz.RunResult, string, error) {
+	var opts fuzz.RunOptions
+	var err error
+	if opts.FuzzTime, err = cmd.Flags().GetDuration(flagFuzzTime); err != nil {
+		return fuzz.RunResult{}, "", err
+	}
+	if opts.Out, err = cmd.Flags().GetString(flagOut); err != nil {
+		return fuzz.RunResult{}, "", err
+	}
+	if opts.FailFast, err = cmd.Flags().GetBool(flagFailFast); err != nil {
+		return fuzz.RunResult{}, "", err
+	}
+	if opts.Deadline, err = cmd.Flags().GetDuration(flagDeadline); err != nil {
+		return fuzz.RunResult{}, "", err
+	}
+	if opts.FailOnEmpty, err = cmd.Flags().GetBool(flagFailOnEmpty); err != nil {
+		return fuzz.RunResult{}, "", err
+	}
+	report, err := cmd.Flags().GetString(flagReport)
 	if err != nil {
-		cmd.PrintErrln(err)
-		os.Exit(1)
+		return fuzz.RunResult{}, "", err
 	}
-
-	failFast, err := cmd.Flags().GetBool(flagFailFast)
+	metricsAddr, err := cmd.Flags().GetString(flagMetricsAddr)
 	if err != nil {
-		cmd.PrintErrln(err)
-		os.Exit(1)
+		return fuzz.RunResult{}, "", err
+	}
+	findings, err := cmd.Flags().GetString(flagFindings)
+	if err != nil {
+		return fuzz.RunResult{}, "", err
+	}
+	if findings != "" {
+		if opts.Findings, err = fuzz.OpenFindingStore(findings); err != nil {
+			return fuzz.RunResult{}, "", err
+		}
+	}
+	if opts.Suppressions, err = loadSuppressions(cmd); err != nil {
+		return fuzz.RunResult{}, "", err
+	}
+	if opts.ContinueSuppressed, err = cmd.Flags().GetBool(flagContinueSuppressed); err != nil {
+		return fuzz.RunResult{}, "", err
+	}
+	if opts.Reproduce, err = cmd.Flags().GetInt(flagReproduce); err != nil {
+		return fuzz.RunResult{}, "", err
+	}
+	if opts.DiscardNonReproducible, err = cmd.Flags().GetBool(flagDiscardNonRepro); err != nil {
+		return fuzz.RunResult{}, "", err
+	}
+	if opts.DiscardNonReproducible && opts.Reproduce <= 0 {
+		return fuzz.RunResult{}, "", errors.New("--discard-non-reproducible requires --reproduce")
 	}
 
-	wd, err := os.Getwd()
+	proj, err := newProject(cmd)
 	if err != nil {
-		cmd.PrintErrln(err)
-		os.Exit(1)
+		return fuzz.RunResult{}, "", err
 	}
 
-	proj := &fuzz.Project{
-		Directory: wd,
-		Quiet:     quiet,
+	if metricsAddr != "" {
+		opts.Metrics = fuzz.NewMetrics()
+		stop, err := serveMetrics(cmd, metricsAddr, opts.Metrics)
+		if err != nil {
+			return fuzz.RunResult{}, "", err
+		}
+		defer stop()
 	}
 
-	packages := []string{"."}
-	if len(args) > 0 {
-		packages = args
+	opts.Packages = args
+	opts.Output = cmd.OutOrStdout()
+	return proj.Run(cmd.Context(), opts), report, nil
+}
+
+// loadSuppressions loads the file given by the suppressions flag, none if it's not defined.
+func loadSuppressions(cmd *cobra.Command) (fuzz.Suppressions, error) {
+	path, err := cmd.Flags().GetString(flagSuppressions)
+	if err != nil || path == "" {
+		return nil, err
 	}
+	return fuzz.LoadSuppressions(path)
+}
 
-	targets, err := proj.ListFuzzTargets(ctx, packages...)
+// serveMetrics serves metrics at /metrics on addr in the background and returns a function stopping the server.
+func serveMetrics(cmd *cobra.Command, addr string, metrics *fuzz.Metrics) (func(), error) {
+	listener, err := net.Listen("tcp", addr)
 	if err != nil {
-		cmd.PrintErrln(err)
-		os.Exit(1)
-	}
-
-	if len(targets) == 0 {
-		cmd.Println("No fuzz tests found")
-		os.Exit(0)
-	}
-
-	timePerTarget := time.Duration(fuzzTime.Milliseconds()/int64(len(targets))) * time.Millisecond
-
-	hasFailures := false
-
-	cmd.Printf("go-ci-fuzz: discovered %d targets, each of them will be fuzzed for %s\n", len(targets), timePerTarget)
-	for _, target := range targets {
-		cmd.Printf("go-ci-fuzz: fuzzing %s for %s\n", target, timePerTarget)
-		if err := proj.Fuzz(ctx, target, timePerTarget); err != nil {
-			hasFailures = true
-			if inputErr, ok := err.(fuzz.FailingInputError); ok {
-				if inputErr.File != "" && out != "" {
-					srcFile := filepath.Join(proj.Directory, inputErr.File)
-					destFile := filepath.Join(out, inputErr.File)
-					destFileFolder := filepath.Dir(destFile)
-
-					if err := os.MkdirAll(destFileFolder, 0755); err != nil {
-						cmd.PrintErrf("error creating %s directory when copying a failing input from %s: %s\n", destFileFolder, inputErr.File, err)
-						os.Exit(1)
-					}
-
-					if err := fuzz.CopyFile(destFile, srcFile, 0644); err != nil {
-						cmd.PrintErrf("copying a failing input from %s to %s: %s\n", srcFile, destFile, err)
-						os.Exit(1)
-					}
-
-					cmd.Printf("Found failing input, saving to %s\n", destFile)
-				} else {
-					cmd.Printf("Found %s, not saving\n", inputErr)
-				}
-			} else {
-				cmd.PrintErrln(err)
-				os.Exit(1)
-			}
-			if failFast {
-				os.Exit(2)
-			}
-		}
+		return nil, fmt.Errorf("cannot serve metrics: %w", err)
 	}
 
-	if hasFailures {
-		os.Exit(2)
-	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics)
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			cmd.PrintErrf("go-ci-fuzz: serving metrics failed: %s\n", err)
+		}
+	}()
+	cmd.Printf("go-ci-fuzz: serving metrics at http://%s/metrics\n", listener.Addr())
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(ctx)
+	}, nil
 }
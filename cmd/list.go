@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list [packages...]",
+	Short: "Lists fuzz targets of packages",
+	Long: `Lists fuzz targets in <packages> in current directory along with the types of their fuzzing arguments.
+Targets without f.Fuzz call or with unsupported argument types are reported with warnings.`,
+	RunE:         listRun,
+	SilenceUsage: true,
+}
+
+func init() {
+	listCmd.Flags().Bool(flagJSON, false, "print targets as JSON")
+}
+
+type listedTarget struct {
+	Name     string   `json:"name"`
+	Package  string   `json:"package"`
+	Args     []string `json:"args"`
+	Seeds    int      `json:"seeds"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+func listRun(cmd *cobra.Command, args []string) error {
+	asJSON, err := cmd.Flags().GetBool(flagJSON)
+	if err != nil {
+		return err
+	}
+
+	proj, err := newProject(cmd)
+	if err != nil {
+		return err
+	}
+
+	packages := []string{"."}
+	if len(args) > 0 {
+		packages = args
+	}
+
+	targets, err := proj.ListFuzzTargets(cmd.Context(), packages...)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		listed := make([]listedTarget, 0, len(targets))
+		for _, target := range targets {
+			listed = append(listed, listedTarget{
+				Name:     target.Name,
+				Package:  target.Package,
+				Args:     target.Args,
+				Seeds:    target.Seeds,
+				Warnings: target.Warnings,
+			})
+		}
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(listed)
+	}
+
+	for _, target := range targets {
+		cmd.Printf("%s(%s)\n", target, strings.Join(target.Args, ", "))
+		for _, warning := range target.Warnings {
+			cmd.Printf("  warning: %s\n", warning)
+		}
+	}
+	return nil
+}
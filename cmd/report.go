@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"encoding/json"
+	"github.com/form3tech-oss/go-ci-fuzz/fuzz"
+	"os"
+)
+
+type runReport struct {
+	ExitCode    int            `json:"exit_code"`
+	Interrupted bool           `json:"interrupted"`
+	Error       string         `json:"error,omitempty"`
+	Elapsed     float64        `json:"elapsed_seconds"`
+	Targets     []targetReport `json:"targets"`
+}
+
+type targetReport struct {
+	Name     string         `json:"name"`
+	Package  string         `json:"package"`
+	Skipped  bool           `json:"skipped"`
+	FuzzTime float64        `json:"fuzz_time_seconds"`
+	Elapsed  float64        `json:"elapsed_seconds"`
+	Failure  *failureReport `json:"failure,omitempty"`
+	// Error describes failures other than plain crashes, e.g. hangs or build errors, with an excerpt of the output.
+	Error      string            `json:"error,omitempty"`
+	Throughput *throughputReport `json:"throughput,omitempty"`
+	// Suppressed are failures matching --suppressions.
+	Suppressed []suppressedReport `json:"suppressed,omitempty"`
+	// Discarded is the non-reproducible failure removed with --discard-non-reproducible.
+	Discarded *failureReport `json:"discarded,omitempty"`
+}
+
+type suppressedReport struct {
+	Kind      string `json:"kind"`
+	ID        string `json:"id"`
+	File      string `json:"file,omitempty"`
+	Seed      bool   `json:"seed"`
+	Saved     string `json:"saved,omitempty"`
+	Signature string `json:"signature"`
+	// Suppression describes the matching suppression including its reason.
+	Suppression string `json:"suppression"`
+	Output      string `json:"output,omitempty"`
+}
+
+type throughputReport struct {
+	Execs          int64            `json:"execs"`
+	ExecsPerSec    float64          `json:"execs_per_sec"`
+	NewInteresting int              `json:"new_interesting"`
+	CorpusSize     int              `json:"corpus_size"`
+	LowExecRate    bool             `json:"low_exec_rate"`
+	Samples        []progressReport `json:"samples"`
+}
+
+type progressReport struct {
+	Elapsed        float64 `json:"elapsed_seconds"`
+	Execs          int64   `json:"execs"`
+	ExecsPerSec    int64   `json:"execs_per_sec"`
+	NewInteresting int     `json:"new_interesting"`
+	CorpusSize     int     `json:"corpus_size"`
+}
+
+type failureReport struct {
+	// Kind is one of crash, hang, oom or race.
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+	File string `json:"file,omitempty"`
+	Seed bool   `json:"seed"`
+	// Saved is the path of the copy in --out.
+	Saved string `json:"saved,omitempty"`
+	// Signature and FindingState are set if --findings is defined, the state is one of new, known or regressed.
+	Signature    string `json:"signature,omitempty"`
+	FindingState string `json:"finding_state,omitempty"`
+	// Reproducibility is set if --reproduce is defined, it's one of reproducible, flaky or non-reproducible.
+	Reproducibility *reproductionReport `json:"reproducibility,omitempty"`
+	// Output is the excerpt of the output describing the failure, e.g. the panic and its stack.
+	Output string `json:"output,omitempty"`
+}
+
+type reproductionReport struct {
+	Verdict  string `json:"verdict"`
+	Runs     int    `json:"runs"`
+	Failures int    `json:"failures"`
+}
+
+func newReproductionReport(reproduction *fuzz.Reproduction) *reproductionReport {
+	if reproduction == nil {
+		return nil
+	}
+	return &reproductionReport{Verdict: reproduction.Verdict(), Runs: reproduction.Runs, Failures: reproduction.Failures}
+}
+
+func newRunReport(result fuzz.RunResult) runReport {
+	report := runReport{
+		ExitCode:    result.ExitCode(),
+		Interrupted: result.Interrupted,
+		Elapsed:     result.Elapsed.Seconds(),
+		Targets:     []targetReport{},
+	}
+	if result.Err != nil {
+		report.Error = result.Err.Error()
+	}
+
+	for _, target := range result.Targets {
+		t := targetReport{
+			Name:     target.Target.Name,
+			Package:  target.Target.Package,
+			Skipped:  target.Skipped,
+			FuzzTime: target.FuzzTime.Seconds(),
+			Elapsed:  target.Elapsed.Seconds(),
+		}
+		if target.Failure != nil {
+			t.Failure = &failureReport{
+				Kind:   fuzz.FailureKind(target.Error),
+				ID:     target.Failure.ID,
+				File:   target.Failure.File,
+				Seed:   target.Failure.Seed,
+				Saved:  target.Saved,
+				Output: fuzz.FailureOutput(target.Error),
+			}
+			if target.Finding != nil {
+				t.Failure.Signature = target.Finding.Signature
+				t.Failure.FindingState = target.FindingState
+			}
+			t.Failure.Reproducibility = newReproductionReport(target.Reproduction)
+		}
+		if target.Discarded != nil {
+			t.Discarded = &failureReport{
+				Kind:            fuzz.FailureKind(target.Error),
+				ID:              target.Discarded.ID,
+				File:            target.Discarded.File,
+				Reproducibility: newReproductionReport(target.Reproduction),
+				Output:          fuzz.FailureOutput(target.Error),
+			}
+		}
+		if target.Error != nil && fuzz.FailureKind(target.Error) != fuzz.FailureCrash {
+			t.Error = target.Error.Error()
+		}
+		if target.Throughput.Reported() {
+			t.Throughput = newThroughputReport(target.Throughput)
+		}
+		for _, suppressed := range target.Suppressed {
+			t.Suppressed = append(t.Suppressed, suppressedReport{
+				Kind:        fuzz.FailureKind(suppressed.Error),
+				ID:          suppressed.Failure.ID,
+				File:        suppressed.Failure.File,
+				Seed:        suppressed.Failure.Seed,
+				Saved:       suppressed.Saved,
+				Signature:   fuzz.Signature(target.Target, suppressed.Error),
+				Suppression: suppressed.Suppression.String(),
+				Output:      fuzz.FailureOutput(suppressed.Error),
+			})
+		}
+		report.Targets = append(report.Targets, t)
+	}
+	return report
+}
+
+func newThroughputReport(throughput fuzz.Throughput) *throughputReport {
+	report := &throughputReport{
+		Execs:          throughput.Final.Execs,
+		ExecsPerSec:    throughput.ExecsPerSec(),
+		NewInteresting: throughput.Final.NewInteresting,
+		CorpusSize:     throughput.Final.Total,
+		LowExecRate:    throughput.Low(),
+		Samples:        []progressReport{},
+	}
+	for _, sample := range throughput.Samples {
+		report.Samples = append(report.Samples, progressReport{
+			Elapsed:        sample.Elapsed.Seconds(),
+			Execs:          sample.Execs,
+			ExecsPerSec:    sample.ExecsPerSec,
+			NewInteresting: sample.NewInteresting,
+			CorpusSize:     sample.Total,
+		})
+	}
+	return report
+}
+
+// writeReport writes the JSON report of result to path.
+func writeReport(path string, result fuzz.RunResult) error {
+	data, err := json.MarshalIndent(newRunReport(result), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
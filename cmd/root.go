@@ -1,13 +1,22 @@
 package cmd
 
 import (
+	"context"
 	"os"
+	"syscall"
 
+	"github.com/form3tech-oss/go-ci-fuzz/fuzz"
 	"github.com/spf13/cobra"
 )
 
 const (
-	flagQuiet = "quiet"
+	flagQuiet   = "quiet"
+	flagConfig  = "config"
+	flagTags    = "tags"
+	flagLdflags = "ldflags"
+	flagGcflags = "gcflags"
+	flagMod     = "mod"
+	flagEnv     = "env"
 )
 
 var rootCmd = &cobra.Command{
@@ -21,8 +30,13 @@ var rootCmd = &cobra.Command{
 	Example: `go-ci-fuzz fuzz ./... --fuzz-time 10m --out /tmp/failing-inputs`,
 }
 
+// Execute runs the root command with a context cancelled on SIGINT or SIGTERM, e.g. when a CI job times out.
+// A second signal kills running 'go' commands and terminates the process immediately.
 func Execute() {
-	err := rootCmd.Execute()
+	ctx, stop := fuzz.NotifyContext(context.Background(), os.Exit, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := rootCmd.ExecuteContext(ctx)
 	if err != nil {
 		os.Exit(1)
 	}
@@ -30,5 +44,15 @@ func Execute() {
 
 func init() {
 	rootCmd.AddCommand(fuzzCmd)
+	rootCmd.AddCommand(corpusCmd)
+	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(findingsCmd)
 	rootCmd.PersistentFlags().Bool(flagQuiet, false, "silences underlying Go CLI StdOut")
+	rootCmd.PersistentFlags().String(flagConfig, "", "config file with build options, "+fuzz.ConfigFile+" in current directory is used if it exists, flags override it")
+	rootCmd.PersistentFlags().StringSlice(flagTags, nil, "build tags used to discover and build targets")
+	rootCmd.PersistentFlags().String(flagLdflags, "", "-ldflags passed to 'go test'")
+	rootCmd.PersistentFlags().String(flagGcflags, "", "-gcflags passed to 'go test'")
+	rootCmd.PersistentFlags().String(flagMod, "", "-mod passed to 'go list' and 'go test', e.g. vendor")
+	rootCmd.PersistentFlags().StringArray(flagEnv, nil, "environment variable KEY=VALUE of 'go' commands, e.g. GOEXPERIMENT=arenas, in addition to those of the config file")
 }
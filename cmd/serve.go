@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/form3tech-oss/go-ci-fuzz/fuzz"
+	"github.com/spf13/cobra"
+	"time"
+)
+
+const (
+	flagStateDir     = "state-dir"
+	flagRemote       = "remote"
+	flagRef          = "ref"
+	flagPullInterval = "pull-interval"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve [packages...]",
+	Short: "Fuzzes targets of packages continuously",
+	Long: `Fuzzes all fuzz targets in <packages> in current directory one after another for --fuzz-time each, in rotation
+until SIGINT or SIGTERM. The current directory must be a git repository dedicated to the session.
+
+Every --pull-interval, --ref is fetched from --remote and checked out detached if --ref is defined and targets
+are discovered again if the checked out commit changed.
+
+Findings are deduplicated by their signature into findings.jsonl in --state-dir, copies of failing inputs are
+written to its inputs directory. A target with a finding or another failure, e.g. a build failure, is skipped
+until a new commit is checked out.
+
+The fuzz cache corpus of every target is persisted to --state-dir and restored if the Go build cache was cleaned.
+
+Failures matching --suppressions, see 'go-ci-fuzz fuzz --help', are neither recorded nor make the target skipped,
+the failing input is moved from the corpus to --state-dir.
+`,
+	Example:      `go-ci-fuzz serve ./... --state-dir /var/lib/go-ci-fuzz --ref main --fuzz-time 10m`,
+	RunE:         serveRun,
+	SilenceUsage: true,
+}
+
+func init() {
+	serveCmd.Flags().Duration(flagFuzzTime, 10*time.Minute, "fuzzing duration of every target before moving on to the next one")
+	serveCmd.Flags().String(flagStateDir, "", "directory to keep findings, failing inputs and the fuzz cache corpus in")
+	_ = serveCmd.MarkFlagRequired(flagStateDir)
+	serveCmd.Flags().String(flagRemote, "origin", "git remote to pull --ref from")
+	serveCmd.Flags().String(flagRef, "", "git ref to pull and check out, the checkout is left as is if not defined")
+	serveCmd.Flags().Duration(flagPullInterval, 10*time.Minute, "how often to pull --ref and check for new commits")
+	serveCmd.Flags().String(flagMetricsAddr, "", "address to serve Prometheus metrics at, e.g. :9090")
+	serveCmd.Flags().Bool(flagRace, false, "fuzz with the race detector enabled")
+	serveCmd.Flags().String(flagSuppressions, "", "JSON file of known failures which are not recorded")
+}
+
+func serveRun(cmd *cobra.Command, args []string) error {
+	var opts fuzz.ServeOptions
+	var err error
+	if opts.FuzzTime, err = cmd.Flags().GetDuration(flagFuzzTime); err != nil {
+		return err
+	}
+	if opts.StateDir, err = cmd.Flags().GetString(flagStateDir); err != nil {
+		return err
+	}
+	if opts.Remote, err = cmd.Flags().GetString(flagRemote); err != nil {
+		return err
+	}
+	if opts.Ref, err = cmd.Flags().GetString(flagRef); err != nil {
+		return err
+	}
+	if opts.PullInterval, err = cmd.Flags().GetDuration(flagPullInterval); err != nil {
+		return err
+	}
+	metricsAddr, err := cmd.Flags().GetString(flagMetricsAddr)
+	if err != nil {
+		return err
+	}
+	if opts.Suppressions, err = loadSuppressions(cmd); err != nil {
+		return err
+	}
+	if opts.FuzzTime <= 0 || opts.PullInterval <= 0 {
+		return errors.New("--fuzz-time and --pull-interval must be positive")
+	}
+
+	proj, err := newProject(cmd)
+	if err != nil {
+		return err
+	}
+
+	if metricsAddr != "" {
+		opts.Metrics = fuzz.NewMetrics()
+		stop, err := serveMetrics(cmd, metricsAddr, opts.Metrics)
+		if err != nil {
+			return err
+		}
+		defer stop()
+	}
+
+	opts.Packages = args
+	opts.Output = cmd.OutOrStdout()
+	return proj.Serve(cmd.Context(), opts)
+}
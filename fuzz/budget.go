@@ -0,0 +1,82 @@
+package fuzz
+
+import (
+	"time"
+)
+
+const (
+	// defaultOverhead is the expected time spent outside of fuzzing per target, e.g. compiling the test binary,
+	// before the first target finishes and the real overhead can be measured.
+	defaultOverhead = 5 * time.Second
+	// minFuzzTime is the shortest time slice worth fuzzing a target for.
+	minFuzzTime = time.Second
+)
+
+// Budget divides the time left until a deadline among targets. The time spent outside of fuzzing,
+// e.g. building test binaries and copying failing inputs, is measured as targets finish and subtracted from
+// the time slices of the remaining targets so that the whole run finishes before the deadline.
+type Budget struct {
+	Deadline time.Time
+	targets  int
+	done     int
+	// overheads are the measured times spent outside of fuzzing by finished targets.
+	overheads []time.Duration
+	now       func() time.Time
+}
+
+func NewBudget(deadline time.Time, targets int) *Budget {
+	return &Budget{Deadline: deadline, targets: targets, now: time.Now}
+}
+
+// Next returns the fuzzing time of the next target, false if there is not enough time left to fuzz it.
+// When the time left doesn't suffice to fuzz all remaining targets for minFuzzTime, it's divided among as many
+// targets as fit and the rest is skipped.
+func (b *Budget) Next() (time.Duration, bool) {
+	left := b.targets - b.done
+	if left <= 0 {
+		return 0, false
+	}
+
+	remaining := b.Deadline.Sub(b.now())
+	overhead := b.overhead()
+	if fit := int(remaining / (minFuzzTime + overhead)); fit < left {
+		left = fit
+	}
+	if left <= 0 {
+		return 0, false
+	}
+
+	slice := remaining/time.Duration(left) - overhead
+	slice = slice.Truncate(time.Millisecond)
+	if slice < minFuzzTime {
+		return 0, false
+	}
+	return slice, true
+}
+
+// Done records that a target was fuzzed for fuzzTime and the whole run, including the overhead, took elapsed.
+func (b *Budget) Done(fuzzTime, elapsed time.Duration) {
+	b.done++
+	// targets failing early don't tell anything about the overhead
+	if elapsed > fuzzTime {
+		b.overheads = append(b.overheads, elapsed-fuzzTime)
+	}
+}
+
+// Remaining returns the number of targets which have not been fuzzed yet.
+func (b *Budget) Remaining() int {
+	return b.targets - b.done
+}
+
+// overhead returns the mean measured overhead per target or defaultOverhead if nothing was measured yet.
+func (b *Budget) overhead() time.Duration {
+	if len(b.overheads) == 0 {
+		return defaultOverhead
+	}
+
+	var total time.Duration
+	for _, overhead := range b.overheads {
+		total += overhead
+	}
+	return total / time.Duration(len(b.overheads))
+}
@@ -0,0 +1,100 @@
+package fuzz
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+func TestBudget(t *testing.T) {
+	t.Run("shrinks time slices by measured overhead", func(t *testing.T) {
+		clock := &fakeClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+		budget := NewBudget(clock.t.Add(10*time.Minute), 3)
+		budget.now = clock.now
+
+		// discovery took 30s
+		clock.t = clock.t.Add(30 * time.Second)
+
+		d, ok := budget.Next()
+		assert.True(t, ok)
+		assert.Equal(t, 190*time.Second-defaultOverhead, d, "first slice assumes the default overhead")
+
+		// compiling took 20s
+		clock.t = clock.t.Add(d + 20*time.Second)
+		budget.Done(d, d+20*time.Second)
+
+		d, ok = budget.Next()
+		assert.True(t, ok)
+		remaining := 10*time.Minute - 30*time.Second - 185*time.Second - 20*time.Second
+		assert.Equal(t, remaining/2-20*time.Second, d)
+
+		clock.t = clock.t.Add(d + 10*time.Second)
+		budget.Done(d, d+10*time.Second)
+
+		d, ok = budget.Next()
+		assert.True(t, ok)
+		assert.Equal(t, clock.t.Add(d+15*time.Second), budget.Deadline, "last slice leaves room for the mean overhead")
+		assert.Equal(t, 1, budget.Remaining())
+
+		budget.Done(d, d+15*time.Second)
+		_, ok = budget.Next()
+		assert.False(t, ok, "all targets are done")
+	})
+
+	t.Run("ignores targets failing early", func(t *testing.T) {
+		clock := &fakeClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+		budget := NewBudget(clock.t.Add(time.Minute), 2)
+		budget.now = clock.now
+
+		budget.Done(25*time.Second, 3*time.Second)
+		clock.t = clock.t.Add(3 * time.Second)
+
+		d, ok := budget.Next()
+		assert.True(t, ok)
+		assert.Equal(t, 57*time.Second-defaultOverhead, d)
+	})
+
+	t.Run("skips targets once the deadline is near", func(t *testing.T) {
+		clock := &fakeClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+		budget := NewBudget(clock.t.Add(10*time.Second), 3)
+		budget.now = clock.now
+
+		d, ok := budget.Next()
+		assert.True(t, ok)
+		assert.Equal(t, 10*time.Second-defaultOverhead, d, "only one target fits")
+		clock.t = clock.t.Add(d + defaultOverhead)
+		budget.Done(d, d+defaultOverhead)
+
+		_, ok = budget.Next()
+		assert.False(t, ok)
+		assert.Equal(t, 2, budget.Remaining())
+	})
+	t.Run("fuzzes as many targets as fit before the deadline", func(t *testing.T) {
+		clock := &fakeClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+		budget := NewBudget(clock.t.Add(time.Minute), 100)
+		budget.now = clock.now
+
+		fuzzed := 0
+		for {
+			d, ok := budget.Next()
+			if !ok {
+				break
+			}
+			assert.GreaterOrEqual(t, d, minFuzzTime)
+			clock.t = clock.t.Add(d + defaultOverhead)
+			budget.Done(d, d+defaultOverhead)
+			fuzzed++
+		}
+		assert.Equal(t, 10, fuzzed, "a minute fits 10 targets fuzzed for a second with the default overhead")
+		assert.Equal(t, 90, budget.Remaining())
+		assert.False(t, clock.t.After(budget.Deadline))
+	})
+}
@@ -0,0 +1,74 @@
+package fuzz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// BuildOptions are applied to every 'go' command run for a project, so that targets are discovered and fuzzed
+// with the same build configuration, e.g. targets behind build tags.
+type BuildOptions struct {
+	// Tags are build tags, e.g. integration.
+	Tags []string `json:"tags,omitempty"`
+	// Ldflags and Gcflags are passed as -ldflags and -gcflags.
+	Ldflags string `json:"ldflags,omitempty"`
+	Gcflags string `json:"gcflags,omitempty"`
+	// Mod is the module download mode passed as -mod, e.g. vendor.
+	Mod string `json:"mod,omitempty"`
+	// Race builds and runs targets with the race detector, data races are reported as RaceError.
+	Race bool `json:"race,omitempty"`
+	// Env are additional environment variables in the form KEY=VALUE, e.g. GOEXPERIMENT=arenas or GOFLAGS=-trimpath.
+	Env []string `json:"env,omitempty"`
+}
+
+// flags returns the build flags of 'go list' and 'go test'.
+func (b BuildOptions) flags() []string {
+	var flags []string
+	if len(b.Tags) > 0 {
+		flags = append(flags, "-tags="+strings.Join(b.Tags, ","))
+	}
+	if b.Ldflags != "" {
+		flags = append(flags, "-ldflags="+b.Ldflags)
+	}
+	if b.Gcflags != "" {
+		flags = append(flags, "-gcflags="+b.Gcflags)
+	}
+	if b.Mod != "" {
+		flags = append(flags, "-mod="+b.Mod)
+	}
+	if b.Race {
+		flags = append(flags, "-race")
+	}
+	return flags
+}
+
+// Validate checks that Env only contains KEY=VALUE pairs.
+func (b BuildOptions) Validate() error {
+	for _, variable := range b.Env {
+		if key, _, ok := strings.Cut(variable, "="); !ok || key == "" {
+			return fmt.Errorf("invalid environment variable %q, KEY=VALUE expected", variable)
+		}
+	}
+	return nil
+}
+
+// goCommand returns 'go <args>' run in the directory of the project with the environment of its build options.
+// Build flags are not added, see BuildOptions.flags.
+func (p *Project) goCommand(ctx context.Context, args ...string) (*exec.Cmd, error) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		return nil, errors.New("go is not installed")
+	}
+	cmd := exec.CommandContext(ctx, goBin, args...)
+	if p.Directory != "" {
+		cmd.Dir = p.Directory
+	}
+	if len(p.Build.Env) > 0 {
+		cmd.Env = append(os.Environ(), p.Build.Env...)
+	}
+	return cmd, nil
+}
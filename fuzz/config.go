@@ -0,0 +1,37 @@
+package fuzz
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// ConfigFile is the name of the configuration file looked up in the current directory.
+const ConfigFile = ".go-ci-fuzz.json"
+
+// Config is the configuration file of a project, e.g.
+//
+//	{"build": {"tags": ["integration"], "env": ["GOEXPERIMENT=arenas"]}}
+type Config struct {
+	Build BuildOptions `json:"build"`
+}
+
+// LoadConfig reads the configuration file at path.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var config Config
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return Config{}, fmt.Errorf("cannot parse config %s: %w", path, err)
+	}
+	if err := config.Build.Validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return config, nil
+}
@@ -0,0 +1,44 @@
+package fuzz
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	write := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), ConfigFile)
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	t.Run("build options", func(t *testing.T) {
+		config, err := LoadConfig(write(t, `{"build": {"tags": ["integration", "e2e"], "ldflags": "-X main.version=1", "mod": "vendor", "race": true, "env": ["GOEXPERIMENT=arenas"]}}`))
+		assert.NoError(t, err)
+		assert.Equal(t, BuildOptions{
+			Tags:    []string{"integration", "e2e"},
+			Ldflags: "-X main.version=1",
+			Mod:     "vendor",
+			Race:    true,
+			Env:     []string{"GOEXPERIMENT=arenas"},
+		}, config.Build)
+		assert.Equal(t, []string{"-tags=integration,e2e", "-ldflags=-X main.version=1", "-mod=vendor", "-race"}, config.Build.flags())
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := LoadConfig(write(t, `{"build": {"tag": ["integration"]}}`))
+		assert.ErrorContains(t, err, "unknown field")
+	})
+
+	t.Run("invalid environment variable", func(t *testing.T) {
+		_, err := LoadConfig(write(t, `{"build": {"env": ["GOEXPERIMENT"]}}`))
+		assert.ErrorContains(t, err, `invalid environment variable "GOEXPERIMENT"`)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := LoadConfig(filepath.Join(t.TempDir(), ConfigFile))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
@@ -32,7 +32,7 @@ func copyDirectory(dest string, src string) error {
 			return nil
 		}
 
-		return CopyFile(destPath, path, 0666)
+		return CopyFile(destPath, path, 0644)
 	})
 
 	if err != nil {
@@ -1,44 +1,52 @@
 package fuzz
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/form3tech-oss/go-ci-fuzz/fuzz/corpusfile"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 )
 
 func (p *Project) CorpusExtract(ctx context.Context, destination string, packages ...string) error {
+	return p.CorpusExtractTo(ctx, NewDirStore(destination), packages...)
+}
+
+// CorpusExtractTo writes corpora of targets in packages to store.
+func (p *Project) CorpusExtractTo(ctx context.Context, store CorpusStore, packages ...string) error {
 	targets, err := p.ListFuzzTargets(ctx, packages...)
 	if err != nil {
 		return err
 	}
 
-	if len(targets) == 0 {
-		return nil
-	}
-
-	if err := os.MkdirAll(destination, 0700); err != nil {
-		return fmt.Errorf("cannot create destination corpus directory: %w", err)
-	}
-
+	local := NewDirStore(p.Directory)
 	for _, target := range targets {
 		corpusDir, err := p.relCorpusDir(target)
 		if err != nil {
 			return fmt.Errorf("cannot get corpus directory path: %w", err)
 		}
+		corpusDir = filepath.ToSlash(corpusDir)
 
-		srcCorpusDir := filepath.Join(p.Directory, corpusDir)
-		if _, err := os.Stat(srcCorpusDir); os.IsNotExist(err) {
-			continue
-		}
-
-		destCorpusDir := filepath.Join(destination, corpusDir)
-		if err := os.MkdirAll(destCorpusDir, 0700); err != nil {
-			return fmt.Errorf("cannot create target destination corpus for %s: %w", destCorpusDir, err)
+		names, err := local.List(ctx, corpusDir)
+		if err != nil {
+			return fmt.Errorf("cannot read corpus directory %s: %w", corpusDir, err)
 		}
 
-		if err := copyDirectory(destCorpusDir, srcCorpusDir); err != nil {
-			return fmt.Errorf("copying %q to %q failed: %w", srcCorpusDir, destCorpusDir, err)
+		for _, name := range names {
+			entry := path.Join(corpusDir, name)
+			data, err := local.Read(ctx, entry)
+			if err != nil {
+				return err
+			}
+			if err := store.Write(ctx, entry, data); err != nil {
+				return fmt.Errorf("writing %s failed: %w", entry, err)
+			}
 		}
 	}
 
@@ -68,32 +76,309 @@ func (p *Project) CorpusDelete(ctx context.Context, packages ...string) error {
 }
 
 func (p *Project) CorpusReplace(ctx context.Context, external string, packages ...string) error {
+	_, err := p.CorpusReplaceFrom(ctx, NewDirStore(external), packages...)
+	return err
+}
+
+// CorpusReplaceFrom replaces corpora of targets in packages with the ones in store.
+func (p *Project) CorpusReplaceFrom(ctx context.Context, store CorpusStore, packages ...string) ([]MergeResult, error) {
 	err := p.CorpusDelete(ctx, packages...)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return p.CorpusMerge(ctx, external, packages...)
+	return p.CorpusMergeFrom(ctx, store, MergeOptions{}, packages...)
 }
 
 func (p *Project) CorpusMerge(ctx context.Context, external string, packages ...string) error {
+	_, err := p.CorpusMergeFrom(ctx, NewDirStore(external), MergeOptions{}, packages...)
+	return err
+}
+
+// ErrCorpusConflict is returned by CorpusMergeFrom when MergeOptions.FailOnConflict is set and conflicting entries were found.
+var ErrCorpusConflict = errors.New("conflicting corpus entries")
+
+type MergeOptions struct {
+	// FailOnConflict makes CorpusMergeFrom return ErrCorpusConflict if any entry conflicts with an existing one.
+	FailOnConflict bool
+}
+
+// MergeResult summarizes merging of a corpus of a single target.
+type MergeResult struct {
+	Target Target
+	// Added is the number of new entries.
+	Added int
+	// Skipped is the number of entries identical to existing ones.
+	Skipped int
+	// Conflicts are names of entries whose content differs from existing entries of the same name, they're left untouched.
+	Conflicts []string
+}
+
+// CorpusMergeFrom copies corpora of targets in packages from store into the project.
+// Corpus entries are content addressed, identical entries are skipped and entries with the same name but different
+// content are reported as conflicts and never overwritten.
+func (p *Project) CorpusMergeFrom(ctx context.Context, store CorpusStore, opts MergeOptions, packages ...string) ([]MergeResult, error) {
 	targets, err := p.ListFuzzTargets(ctx, packages...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	var results []MergeResult
+	conflicts := 0
 	for _, target := range targets {
 		corpusDir, err := p.relCorpusDir(target)
 		if err != nil {
-			return fmt.Errorf("cannot get corpus directory path: %w", err)
+			return nil, fmt.Errorf("cannot get corpus directory path: %w", err)
 		}
 
+		names, err := store.List(ctx, filepath.ToSlash(corpusDir))
+		if err != nil {
+			return nil, fmt.Errorf("listing corpus of %s failed: %w", target, err)
+		}
+
+		result := MergeResult{Target: target}
 		currentCorpusDir := filepath.Join(p.Directory, corpusDir)
-		externalCorpusDir := filepath.Join(external, corpusDir)
+		for _, name := range names {
+			data, err := store.Read(ctx, path.Join(filepath.ToSlash(corpusDir), name))
+			if err != nil {
+				return nil, fmt.Errorf("reading corpus entry %s of %s failed: %w", name, target, err)
+			}
+
+			dest := filepath.Join(currentCorpusDir, name)
+			existing, err := os.ReadFile(dest)
+			if err == nil {
+				if bytes.Equal(existing, data) {
+					result.Skipped++
+				} else {
+					result.Conflicts = append(result.Conflicts, name)
+				}
+				continue
+			} else if !os.IsNotExist(err) {
+				return nil, err
+			}
 
-		if err := copyDirectory(currentCorpusDir, externalCorpusDir); err != nil {
-			return fmt.Errorf("copying %q to %q failed: %w", externalCorpusDir, currentCorpusDir, err)
+			if err := os.MkdirAll(currentCorpusDir, 0755); err != nil {
+				return nil, fmt.Errorf("cannot create corpus directory %s: %w", currentCorpusDir, err)
+			}
+			if err := os.WriteFile(dest, data, 0644); err != nil {
+				return nil, err
+			}
+			result.Added++
 		}
+
+		conflicts += len(result.Conflicts)
+		results = append(results, result)
 	}
 
-	return nil
+	if opts.FailOnConflict && conflicts > 0 {
+		return results, fmt.Errorf("%w: %d entries differ from existing ones", ErrCorpusConflict, conflicts)
+	}
+	return results, nil
+}
+
+// FindCorpusEntry resolves an entry ID in the form of FuzzTarget/<name>, as printed by 'go test',
+// against the targets discovered in packages and returns the paths of the matching corpus files.
+func (p *Project) FindCorpusEntry(ctx context.Context, id string, packages ...string) ([]string, error) {
+	name, entry, ok := strings.Cut(id, "/")
+	if !ok || name == "" || entry == "" || strings.ContainsAny(entry, `/\`) {
+		return nil, fmt.Errorf("invalid corpus entry id %q, expected FuzzTarget/<name>", id)
+	}
+
+	targets, err := p.ListFuzzTargets(ctx, packages...)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, target := range targets {
+		if target.Name != name {
+			continue
+		}
+
+		corpusDir, err := p.relCorpusDir(target)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get corpus directory path: %w", err)
+		}
+
+		path := filepath.Join(p.Directory, corpusDir, entry)
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		}
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("corpus entry %q not found", id)
+	}
+	return paths, nil
+}
+
+type ImportResult struct {
+	Imported int
+	Skipped  int
+}
+
+// CorpusImport converts raw files in src, e.g. corpora of go-fuzz or libFuzzer, into corpus entries of target.
+// Only targets accepting a single []byte or string argument, as detected during discovery, are supported.
+// Entries are named by their content hash so files whose entry already exists are skipped.
+// Hidden files and directories as well as go-fuzz side files, see isImportIgnored, are not imported.
+func (p *Project) CorpusImport(ctx context.Context, target Target, src string) (ImportResult, error) {
+	var result ImportResult
+
+	args := target.Args
+	if args == nil {
+		return result, fmt.Errorf("arguments of %s are unknown: %s", target, strings.Join(target.Warnings, ", "))
+	}
+	if len(args) != 1 || (args[0] != "[]byte" && args[0] != "[]uint8" && args[0] != "string") {
+		return result, fmt.Errorf("importing raw files requires a single []byte or string argument, %s accepts (%s)", target, strings.Join(args, ", "))
+	}
+
+	corpusDir, err := p.relCorpusDir(target)
+	if err != nil {
+		return result, fmt.Errorf("cannot get corpus directory path: %w", err)
+	}
+	destCorpusDir := filepath.Join(p.Directory, corpusDir)
+
+	err = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if path != src && isImportIgnored(d) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		var val any = content
+		if args[0] == "string" {
+			val = string(content)
+		}
+
+		_, created, err := corpusfile.WriteFile(destCorpusDir, val)
+		if err != nil {
+			return fmt.Errorf("writing corpus entry for %s: %w", path, err)
+		}
+		if created {
+			result.Imported++
+		} else {
+			result.Skipped++
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// isImportIgnored reports whether d is not a raw input, i.e. hidden files and directories such as .DS_Store,
+// the *.output and *.quoted files go-fuzz writes next to crashers and the go-fuzz suppressions directory.
+func isImportIgnored(d fs.DirEntry) bool {
+	name := d.Name()
+	if strings.HasPrefix(name, ".") {
+		return true
+	}
+	if d.IsDir() {
+		return name == "suppressions"
+	}
+	return strings.HasSuffix(name, ".output") || strings.HasSuffix(name, ".quoted")
+}
+
+// ExportResult counts exported corpus entries, Skipped are the errors of entries which could not be exported.
+type ExportResult struct {
+	Exported int
+	Skipped  []error
+}
+
+// CorpusExportRaw writes corpus entries of targets in packages to store using the same structure as CorpusExtractTo.
+// Entries with a single []byte or string value are written as raw files, all others, including entries with a single
+// value of another type, e.g. int or rune, are written as JSON documents named <entry>.json.
+// Entries which cannot be read or decoded are skipped, only failures to write to store abort the export.
+func (p *Project) CorpusExportRaw(ctx context.Context, store CorpusStore, packages ...string) (ExportResult, error) {
+	var result ExportResult
+
+	targets, err := p.ListFuzzTargets(ctx, packages...)
+	if err != nil {
+		return result, err
+	}
+
+	local := NewDirStore(p.Directory)
+	for _, target := range targets {
+		corpusDir, err := p.relCorpusDir(target)
+		if err != nil {
+			return result, fmt.Errorf("cannot get corpus directory path: %w", err)
+		}
+		corpusDir = filepath.ToSlash(corpusDir)
+
+		names, err := local.List(ctx, corpusDir)
+		if err != nil {
+			return result, fmt.Errorf("cannot read corpus directory %s: %w", corpusDir, err)
+		}
+
+		for _, name := range names {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+
+			entry := path.Join(corpusDir, name)
+			rawName, raw, err := readRawEntry(ctx, local, entry)
+			if err != nil {
+				result.Skipped = append(result.Skipped, fmt.Errorf("skipping corpus entry %s of %s: %w", name, target, err))
+				continue
+			}
+			if err := store.Write(ctx, path.Join(corpusDir, rawName), raw); err != nil {
+				return result, fmt.Errorf("writing %s failed: %w", entry, err)
+			}
+			result.Exported++
+		}
+	}
+
+	return result, nil
+}
+
+// readRawEntry reads entry from store and returns the name and content of its raw representation.
+func readRawEntry(ctx context.Context, store CorpusStore, entry string) (string, []byte, error) {
+	data, err := store.Read(ctx, entry)
+	if err != nil {
+		return "", nil, err
+	}
+
+	vals, err := corpusfile.Unmarshal(data)
+	if err != nil {
+		return "", nil, fmt.Errorf("decoding failed: %w", err)
+	}
+	return rawEntry(path.Base(entry), vals)
+}
+
+// rawEntry returns the name and content of the raw representation of an entry.
+func rawEntry(name string, vals []any) (string, []byte, error) {
+	if len(vals) == 1 {
+		switch v := vals[0].(type) {
+		case []byte:
+			return name, v, nil
+		case string:
+			return name, []byte(v), nil
+		}
+	}
+
+	doc := struct {
+		Values []corpusfile.Value `json:"values"`
+	}{}
+	for _, val := range vals {
+		doc.Values = append(doc.Values, corpusfile.Describe(val))
+	}
+
+	content, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", nil, err
+	}
+	return name + ".json", append(content, '\n'), nil
 }
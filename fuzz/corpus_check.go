@@ -0,0 +1,124 @@
+package fuzz
+
+import (
+	"context"
+	"fmt"
+	"github.com/form3tech-oss/go-ci-fuzz/fuzz/corpusfile"
+	"os"
+	"path/filepath"
+)
+
+// CorpusIssue is a corpus entry which cannot be used by its target.
+type CorpusIssue struct {
+	Target Target
+	// File is the path of the entry relative to the project directory.
+	File   string
+	Reason string
+}
+
+func (c CorpusIssue) String() string {
+	return fmt.Sprintf("%s: %s", c.File, c.Reason)
+}
+
+// canonicalArguments maps aliases to the type names reported by corpusfile.TypeName.
+var canonicalArguments = map[string]string{
+	"byte":    "uint8",
+	"rune":    "int32",
+	"[]uint8": "[]byte",
+}
+
+func canonicalArgument(typ string) string {
+	if canonical, ok := canonicalArguments[typ]; ok {
+		return canonical
+	}
+	return typ
+}
+
+// CorpusCheck decodes corpus entries of targets discovered by ListFuzzTargets and compares the number and types
+// of their values with the arguments of the target. Targets with warnings are skipped, their arguments may be unknown.
+func (p *Project) CorpusCheck(ctx context.Context, targets []Target) ([]CorpusIssue, error) {
+	var issues []CorpusIssue
+	for _, target := range targets {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if len(target.Warnings) > 0 {
+			continue
+		}
+
+		corpusDir, err := p.relCorpusDir(target)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get corpus directory path: %w", err)
+		}
+
+		entries, err := os.ReadDir(filepath.Join(p.Directory, corpusDir))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("cannot read corpus directory %s: %w", corpusDir, err)
+		}
+
+		for _, entry := range entries {
+			if !entry.Type().IsRegular() {
+				continue
+			}
+
+			file := filepath.Join(corpusDir, entry.Name())
+			content, err := os.ReadFile(filepath.Join(p.Directory, file))
+			if err != nil {
+				return nil, err
+			}
+
+			if reason := checkCorpusEntry(target, content); reason != "" {
+				issues = append(issues, CorpusIssue{Target: target, File: file, Reason: reason})
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+func checkCorpusEntry(target Target, content []byte) string {
+	vals, err := corpusfile.Unmarshal(content)
+	if err != nil {
+		return err.Error()
+	}
+
+	if len(vals) != len(target.Args) {
+		return fmt.Sprintf("has %d values, %s expects %d", len(vals), target, len(target.Args))
+	}
+
+	for i, val := range vals {
+		typ := corpusfile.TypeName(val)
+		if typ != canonicalArgument(target.Args[i]) {
+			return fmt.Sprintf("value %d is %s, %s expects %s", i, typ, target, target.Args[i])
+		}
+	}
+	return ""
+}
+
+// CorpusQuarantine moves entries of issues to quarantine preserving their path relative to the project directory.
+// The entries are deleted if quarantine is empty.
+func (p *Project) CorpusQuarantine(issues []CorpusIssue, quarantine string) error {
+	for _, issue := range issues {
+		src := filepath.Join(p.Directory, issue.File)
+		if quarantine == "" {
+			if err := os.Remove(src); err != nil {
+				return fmt.Errorf("removing corpus entry %s: %w", issue.File, err)
+			}
+			continue
+		}
+
+		dest := filepath.Join(quarantine, issue.File)
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return fmt.Errorf("cannot create quarantine directory: %w", err)
+		}
+		if err := CopyFile(dest, src, 0644); err != nil {
+			return fmt.Errorf("copying corpus entry %s to %s: %w", issue.File, dest, err)
+		}
+		if err := os.Remove(src); err != nil {
+			return fmt.Errorf("removing corpus entry %s: %w", issue.File, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package fuzz
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCorpusCheck(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reports mismatched entries", func(t *testing.T) {
+		project := Project{Directory: "./testdata/corpus/check"}
+
+		issues, err := project.CorpusCheck(ctx, listTargets(t, project))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		var files []string
+		for _, issue := range issues {
+			assert.Equal(t, "FuzzTarget", issue.Target.Name)
+			assert.NotEmpty(t, issue.Reason)
+			files = append(files, issue.File)
+		}
+		assert.ElementsMatch(t, []string{
+			"testdata/fuzz/FuzzTarget/ff9f899cf2252ff1",
+			"testdata/fuzz/FuzzTarget/049371bd0d36c648",
+			"testdata/fuzz/FuzzTarget/4f2847706562a28a",
+		}, files, "entries of FuzzAliases use aliased types and must be valid")
+	})
+
+	t.Run("quarantines mismatched entries", func(t *testing.T) {
+		tempDir := t.TempDir()
+		if err := copyDirectory(tempDir, "./testdata/corpus/check"); err != nil {
+			t.Fatal(err)
+		}
+		quarantine := t.TempDir()
+
+		project := Project{Directory: tempDir}
+		issues, err := project.CorpusCheck(ctx, listTargets(t, project))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		err = project.CorpusQuarantine(issues, quarantine)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		files, err := listFilesRecursively(quarantine)
+		assert.NoError(t, err)
+		assert.Len(t, files, 3)
+
+		issues, err = project.CorpusCheck(ctx, listTargets(t, project))
+		assert.NoError(t, err)
+		assert.Empty(t, issues)
+
+		_, err = os.Stat(filepath.Join(tempDir, "testdata/fuzz/FuzzTarget/5bf3e3c464cbd6bc"))
+		assert.NoError(t, err, "valid entry must be kept")
+	})
+
+	t.Run("removes mismatched entries", func(t *testing.T) {
+		tempDir := t.TempDir()
+		if err := copyDirectory(tempDir, "./testdata/corpus/check"); err != nil {
+			t.Fatal(err)
+		}
+
+		project := Project{Directory: tempDir}
+		issues, err := project.CorpusCheck(ctx, listTargets(t, project))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		err = project.CorpusQuarantine(issues, "")
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		files, err := listFilesRecursively(filepath.Join(tempDir, "testdata/fuzz"))
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{
+			"FuzzTarget/5bf3e3c464cbd6bc",
+			"FuzzAliases/2178121f7eaf99dc",
+			"FuzzAliases/31701f5ac131332f",
+		}, files)
+	})
+}
+
+func listTargets(t *testing.T, project Project) []Target {
+	t.Helper()
+	targets, err := project.ListFuzzTargets(context.Background(), "...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return targets
+}
@@ -0,0 +1,104 @@
+package fuzz
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+// CorpusDiffEntry is a corpus entry present in at least one of the compared stores, A or B is nil if it is missing.
+type CorpusDiffEntry struct {
+	// Name is the slash separated path of the entry within the stores, e.g. sub/testdata/fuzz/FuzzTarget/0a7e5e215d8c088d.
+	Name string
+	A    []byte
+	B    []byte
+}
+
+// CorpusDiffResult lists differences between corpora of a single target.
+type CorpusDiffResult struct {
+	Target    Target
+	OnlyInA   []CorpusDiffEntry
+	OnlyInB   []CorpusDiffEntry
+	Differing []CorpusDiffEntry
+}
+
+// Empty reports whether the corpora of the target are identical.
+func (d CorpusDiffResult) Empty() bool {
+	return len(d.OnlyInA) == 0 && len(d.OnlyInB) == 0 && len(d.Differing) == 0
+}
+
+// CorpusDiff compares corpora of targets in packages stored in a and b, e.g. a CI artifact and the project itself.
+// A result is returned for every target, see CorpusDiffResult.Empty.
+func (p *Project) CorpusDiff(ctx context.Context, a, b CorpusStore, packages ...string) ([]CorpusDiffResult, error) {
+	targets, err := p.ListFuzzTargets(ctx, packages...)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []CorpusDiffResult
+	for _, target := range targets {
+		corpusDir, err := p.relCorpusDir(target)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get corpus directory path: %w", err)
+		}
+		corpusDir = filepath.ToSlash(corpusDir)
+
+		entriesA, err := readCorpus(ctx, a, corpusDir)
+		if err != nil {
+			return nil, fmt.Errorf("reading corpus of %s failed: %w", target, err)
+		}
+		entriesB, err := readCorpus(ctx, b, corpusDir)
+		if err != nil {
+			return nil, fmt.Errorf("reading corpus of %s failed: %w", target, err)
+		}
+
+		result := CorpusDiffResult{Target: target}
+		for _, name := range sortedKeys(entriesA) {
+			other, ok := entriesB[name]
+			if !ok {
+				result.OnlyInA = append(result.OnlyInA, CorpusDiffEntry{Name: name, A: entriesA[name]})
+			} else if !bytes.Equal(entriesA[name], other) {
+				result.Differing = append(result.Differing, CorpusDiffEntry{Name: name, A: entriesA[name], B: other})
+			}
+		}
+		for _, name := range sortedKeys(entriesB) {
+			if _, ok := entriesA[name]; !ok {
+				result.OnlyInB = append(result.OnlyInB, CorpusDiffEntry{Name: name, B: entriesB[name]})
+			}
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// readCorpus reads all entries of a corpus directory in store keyed by their path.
+func readCorpus(ctx context.Context, store CorpusStore, corpusDir string) (map[string][]byte, error) {
+	names, err := store.List(ctx, corpusDir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string][]byte, len(names))
+	for _, name := range names {
+		entry := path.Join(corpusDir, name)
+		data, err := store.Read(ctx, entry)
+		if err != nil {
+			return nil, err
+		}
+		entries[entry] = data
+	}
+	return entries, nil
+}
+
+func sortedKeys(m map[string][]byte) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,50 @@
+package fuzz
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCorpusDiff(t *testing.T) {
+	ctx := context.Background()
+	project := Project{Directory: "./testdata/corpus/multiple"}
+
+	const (
+		existing = "0a7e5e215d8c088d4b9c4993d0189a07e81603fbdf64f2ca44738aa27159acef"
+		seed     = "go test fuzz v1\nstring(\"z\")\n"
+		changed  = "go test fuzz v1\nstring(\"y\")\n"
+		added    = "go test fuzz v1\nstring(\"x\")\n"
+	)
+
+	b := NewDirStore(t.TempDir())
+	assert.NoError(t, b.Write(ctx, "sub/testdata/fuzz/FuzzSubTarget/"+existing, []byte(changed)))
+	assert.NoError(t, b.Write(ctx, "testdata/fuzz/FuzzTarget/"+existing, []byte(seed)))
+	assert.NoError(t, b.Write(ctx, "testdata/fuzz/FuzzTarget/added", []byte(added)))
+
+	results, err := project.CorpusDiff(ctx, NewDirStore(project.Directory), b, "...")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	diffs := map[string]CorpusDiffResult{}
+	for _, result := range results {
+		diffs[result.Target.String()] = result
+	}
+
+	assert.True(t, diffs["multiple/nocorpus#FuzzSubTarget"].Empty())
+
+	target := diffs["multiple#FuzzTarget"]
+	assert.Empty(t, target.OnlyInA)
+	assert.Empty(t, target.Differing)
+	assert.Equal(t, []CorpusDiffEntry{{Name: "testdata/fuzz/FuzzTarget/added", B: []byte(added)}}, target.OnlyInB)
+
+	sub := diffs["multiple/sub#FuzzSubTarget"]
+	assert.Empty(t, sub.OnlyInA)
+	assert.Empty(t, sub.OnlyInB)
+	assert.Equal(t, []CorpusDiffEntry{{
+		Name: "sub/testdata/fuzz/FuzzSubTarget/" + existing,
+		A:    []byte(seed),
+		B:    []byte(changed),
+	}}, sub.Differing)
+}
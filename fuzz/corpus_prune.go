@@ -0,0 +1,206 @@
+package fuzz
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// OrphanedCorpora returns corpus directories, i.e. <package>/testdata/fuzz/<FuzzTarget>, relative to the project
+// directory which don't belong to any fuzz target of the module, e.g. after a target has been renamed or deleted.
+//
+// Targets are looked up in all test files of the package regardless of build constraints, unlike ListFuzzTargets,
+// so that corpora of targets excluded by build tags or GOOS/GOARCH are not orphaned. Corpora of packages with test
+// files which cannot be parsed are never orphaned.
+func (p *Project) OrphanedCorpora(ctx context.Context) ([]string, error) {
+	corpusDirs, err := p.listCorpusDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	var orphans []string
+	packages := map[string]map[string]bool{}
+	for _, corpusDir := range corpusDirs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		pkgDir := corpusPackageDir(corpusDir)
+		known, ok := packages[pkgDir]
+		if !ok {
+			// the targets of packages which cannot be parsed are unknown, none of their corpora is orphaned
+			known, _ = p.packageFuzzTargets(pkgDir)
+			packages[pkgDir] = known
+		}
+		if known != nil && !known[filepath.Base(corpusDir)] {
+			orphans = append(orphans, corpusDir)
+		}
+	}
+	return orphans, nil
+}
+
+// corpusPackageDir returns the directory of the package of corpusDir, i.e. <package>/testdata/fuzz/<FuzzTarget>.
+func corpusPackageDir(corpusDir string) string {
+	return filepath.Dir(filepath.Dir(filepath.Dir(corpusDir)))
+}
+
+// packageFuzzTargets returns the names of fuzz targets declared in the test files of the package in pkgDir relative
+// to the project directory, build constraints are ignored.
+func (p *Project) packageFuzzTargets(pkgDir string) (map[string]bool, error) {
+	entries, err := os.ReadDir(filepath.Join(p.Directory, pkgDir))
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+		testFile := filepath.Join(p.Directory, pkgDir, entry.Name())
+		f, err := parser.ParseFile(token.NewFileSet(), testFile, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if ok && fn.Recv == nil && strings.HasPrefix(fn.Name.Name, "Fuzz") && isFuzzTarget(fn) {
+				names[fn.Name.Name] = true
+			}
+		}
+	}
+	return names, nil
+}
+
+// listCorpusDirs walks the module and returns all testdata/fuzz/* directories relative to the project directory.
+// Directories ignored by the go tool as well as nested modules are skipped.
+func (p *Project) listCorpusDirs() ([]string, error) {
+	root := p.Directory
+	if root == "" {
+		root = "."
+	}
+
+	var corpusDirs []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || path == root {
+			return nil
+		}
+
+		name := d.Name()
+		if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "vendor" {
+			return filepath.SkipDir
+		}
+		if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+			return filepath.SkipDir
+		}
+		if name != "testdata" {
+			return nil
+		}
+
+		// testdata is not a package, any corpora nested deeper cannot belong to a target
+		entries, err := os.ReadDir(filepath.Join(path, "fuzz"))
+		if os.IsNotExist(err) {
+			return filepath.SkipDir
+		} else if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			rel, err := filepath.Rel(root, filepath.Join(path, "fuzz", entry.Name()))
+			if err != nil {
+				return err
+			}
+			corpusDirs = append(corpusDirs, rel)
+		}
+		return filepath.SkipDir
+	})
+
+	return corpusDirs, err
+}
+
+// CorpusRename moves entries of orphaned corpus directories to the corpus of the target they've been renamed to
+// in the same package. renames maps old target names to new ones.
+// Entries already present in the destination are dropped. It returns the moved directories mapped to their destination.
+func (p *Project) CorpusRename(ctx context.Context, orphans []string, renames map[string]string) (map[string]string, error) {
+	moved := map[string]string{}
+	if len(renames) == 0 {
+		return moved, nil
+	}
+
+	for _, orphan := range orphans {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		newName, ok := renames[filepath.Base(orphan)]
+		if !ok {
+			continue
+		}
+
+		known, err := p.packageFuzzTargets(corpusPackageDir(orphan))
+		if err != nil {
+			return nil, fmt.Errorf("cannot find fuzz targets of %s: %w", corpusPackageDir(orphan), err)
+		}
+		dest := filepath.Join(filepath.Dir(orphan), newName)
+		if !known[newName] {
+			return nil, fmt.Errorf("cannot rename %s, there is no %s target in the same package", orphan, newName)
+		}
+
+		if err := moveCorpus(filepath.Join(p.Directory, dest), filepath.Join(p.Directory, orphan)); err != nil {
+			return nil, fmt.Errorf("moving %s to %s failed: %w", orphan, dest, err)
+		}
+		moved[orphan] = dest
+	}
+
+	return moved, nil
+}
+
+func moveCorpus(dest string, src string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		srcPath := filepath.Join(src, entry.Name())
+		destPath := filepath.Join(dest, entry.Name())
+		if _, err := os.Stat(destPath); err == nil {
+			// entries are content addressed, same name means same content
+			continue
+		}
+		if err := os.Rename(srcPath, destPath); err != nil {
+			return err
+		}
+	}
+
+	return os.RemoveAll(src)
+}
+
+// CorpusPrune deletes the given corpus directories relative to the project directory.
+func (p *Project) CorpusPrune(corpusDirs []string) error {
+	for _, corpusDir := range corpusDirs {
+		if err := os.RemoveAll(filepath.Join(p.Directory, corpusDir)); err != nil {
+			return fmt.Errorf("error deleting corpus directory %s: %w", corpusDir, err)
+		}
+	}
+	return nil
+}
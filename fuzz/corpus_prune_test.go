@@ -0,0 +1,112 @@
+package fuzz
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+func TestOrphanedCorpora(t *testing.T) {
+	project := Project{Directory: "./testdata/corpus/multiple"}
+	ctx := context.Background()
+
+	orphans, err := project.OrphanedCorpora(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sub/testdata/fuzz/FuzzNonExistingTarget"}, orphans)
+}
+
+func TestOrphanedCorporaIgnoresBuildConstraints(t *testing.T) {
+	project := Project{Directory: "./testdata/corpus/constraints"}
+	ctx := context.Background()
+
+	orphans, err := project.OrphanedCorpora(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"testdata/fuzz/FuzzRemoved"}, orphans,
+		"corpora of targets excluded by build tags or GOOS as well as of unparsable packages must not be orphaned")
+
+	t.Run("renames to targets excluded by build tags", func(t *testing.T) {
+		tempDir := t.TempDir()
+		if err := copyDirectory(tempDir, "./testdata/corpus/constraints"); err != nil {
+			t.Fatal(err)
+		}
+		project := Project{Directory: tempDir}
+
+		moved, err := project.CorpusRename(ctx, orphans, map[string]string{"FuzzRemoved": "FuzzIntegration"})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"testdata/fuzz/FuzzRemoved": "testdata/fuzz/FuzzIntegration"}, moved)
+	})
+
+	t.Run("project path with glob metacharacters", func(t *testing.T) {
+		tempDir := filepath.Join(t.TempDir(), "[project]")
+		if err := copyDirectory(tempDir, "./testdata/corpus/constraints"); err != nil {
+			t.Fatal(err)
+		}
+		project := Project{Directory: tempDir}
+
+		orphans, err := project.OrphanedCorpora(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"testdata/fuzz/FuzzRemoved"}, orphans)
+	})
+}
+
+func TestCorpusRename(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+	if err := copyDirectory(tempDir, "./testdata/corpus/multiple"); err != nil {
+		t.Fatal(err)
+	}
+	project := Project{Directory: tempDir}
+
+	orphans, err := project.OrphanedCorpora(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	t.Run("fails for unknown target", func(t *testing.T) {
+		_, err := project.CorpusRename(ctx, orphans, map[string]string{"FuzzNonExistingTarget": "FuzzTarget"})
+		assert.Error(t, err, "FuzzTarget is not in the sub package")
+	})
+
+	t.Run("moves corpus to renamed target", func(t *testing.T) {
+		moved, err := project.CorpusRename(ctx, orphans, map[string]string{"FuzzNonExistingTarget": "FuzzSubTarget"})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"sub/testdata/fuzz/FuzzNonExistingTarget": "sub/testdata/fuzz/FuzzSubTarget"}, moved)
+
+		files, err := listFilesRecursively(filepath.Join(tempDir, "sub/testdata"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"fuzz/FuzzSubTarget/0a7e5e215d8c088d4b9c4993d0189a07e81603fbdf64f2ca44738aa27159acef"}, files)
+
+		orphans, err := project.OrphanedCorpora(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, orphans)
+	})
+}
+
+func TestCorpusPrune(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+	if err := copyDirectory(tempDir, "./testdata/corpus/multiple"); err != nil {
+		t.Fatal(err)
+	}
+	project := Project{Directory: tempDir}
+
+	orphans, err := project.OrphanedCorpora(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	err = project.CorpusPrune(orphans)
+	assert.NoError(t, err)
+
+	files, err := listFilesRecursively(tempDir)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"go.mod",
+		"main_test.go",
+		"nocorpus/main_test.go",
+		"sub/main_test.go",
+		"testdata/fuzz/FuzzTarget/0a7e5e215d8c088d4b9c4993d0189a07e81603fbdf64f2ca44738aa27159acef",
+		"sub/testdata/fuzz/FuzzSubTarget/0a7e5e215d8c088d4b9c4993d0189a07e81603fbdf64f2ca44738aa27159acef",
+	}, files)
+}
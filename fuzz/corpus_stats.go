@@ -0,0 +1,121 @@
+package fuzz
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CorpusStats describes the corpus of a single target, i.e. seed files in testdata/fuzz and entries
+// generated by the fuzzing engine in the Go build cache.
+type CorpusStats struct {
+	Target Target
+	// SeedFiles is the number of entries in the testdata/fuzz directory of the target.
+	SeedFiles int
+	// SeedCalls is the number of f.Add calls found during discovery.
+	SeedCalls int
+	// CacheEntries is the number of entries in $GOCACHE/fuzz/<package>/<FuzzTarget>.
+	CacheEntries int
+	// TotalSize and MaxSize are sizes of seed files and cache entries in bytes.
+	TotalSize int64
+	MaxSize   int64
+	// Newest is the modification time of the newest seed file or cache entry, zero if there are none.
+	Newest time.Time
+}
+
+// Entries returns the number of seed files and cache entries.
+func (s CorpusStats) Entries() int {
+	return s.SeedFiles + s.CacheEntries
+}
+
+// MeanSize returns the mean size of seed files and cache entries in bytes.
+func (s CorpusStats) MeanSize() int64 {
+	if s.Entries() == 0 {
+		return 0
+	}
+	return s.TotalSize / int64(s.Entries())
+}
+
+// CorpusStats collects statistics of corpora of targets in packages.
+func (p *Project) CorpusStats(ctx context.Context, packages ...string) ([]CorpusStats, error) {
+	targets, err := p.ListFuzzTargets(ctx, packages...)
+	if err != nil {
+		return nil, err
+	}
+
+	cache, err := p.goEnv(ctx, "GOCACHE")
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []CorpusStats
+	for _, target := range targets {
+		corpusDir, err := p.relCorpusDir(target)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get corpus directory path: %w", err)
+		}
+
+		s := CorpusStats{Target: target, SeedCalls: target.Seeds}
+		s.SeedFiles, err = s.add(filepath.Join(p.Directory, corpusDir))
+		if err != nil {
+			return nil, err
+		}
+		if cache != "" && cache != "off" {
+			s.CacheEntries, err = s.add(filepath.Join(cache, "fuzz", filepath.FromSlash(target.Package), target.Name))
+			if err != nil {
+				return nil, err
+			}
+		}
+		stats = append(stats, s)
+	}
+
+	return stats, nil
+}
+
+// add accounts entries of dir and returns their number, missing directories have no entries.
+func (s *CorpusStats) add(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("cannot read corpus directory %s: %w", dir, err)
+	}
+
+	count := 0
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return 0, err
+		}
+
+		count++
+		s.TotalSize += info.Size()
+		if info.Size() > s.MaxSize {
+			s.MaxSize = info.Size()
+		}
+		if info.ModTime().After(s.Newest) {
+			s.Newest = info.ModTime()
+		}
+	}
+	return count, nil
+}
+
+// goEnv returns the value of a go environment variable as printed by 'go env'.
+func (p *Project) goEnv(ctx context.Context, name string) (string, error) {
+	cmd, err := p.goCommand(ctx, "env", name)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("cannot get %s: %w", name, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,60 @@
+package fuzz
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCorpusStats(t *testing.T) {
+	ctx := context.Background()
+	project := Project{Directory: "./testdata/corpus/multiple"}
+
+	cache := t.TempDir()
+	t.Setenv("GOCACHE", cache)
+
+	cacheDir := filepath.Join(cache, "fuzz", "multiple", "FuzzTarget")
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cacheDir, "a"), make([]byte, 100), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cacheDir, "b"), make([]byte, 50), 0644); err != nil {
+		t.Fatal(err)
+	}
+	newest := time.Now().Add(time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(filepath.Join(cacheDir, "b"), newest, newest); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := project.CorpusStats(ctx, "...")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	byTarget := map[string]CorpusStats{}
+	for _, s := range stats {
+		byTarget[s.Target.String()] = s
+	}
+
+	seed := int64(len("go test fuzz v1\nstring(\"z\")\n"))
+
+	target := byTarget["multiple#FuzzTarget"]
+	assert.Equal(t, 1, target.SeedFiles)
+	assert.Equal(t, 1, target.SeedCalls)
+	assert.Equal(t, 2, target.CacheEntries)
+	assert.Equal(t, seed+150, target.TotalSize)
+	assert.Equal(t, int64(100), target.MaxSize)
+	assert.Equal(t, (seed+150)/3, target.MeanSize())
+	assert.True(t, newest.Equal(target.Newest), "newest entry must be the cache entry, got %s", target.Newest)
+
+	nocorpus := byTarget["multiple/nocorpus#FuzzSubTarget"]
+	assert.Equal(t, 0, nocorpus.Entries())
+	assert.Equal(t, 2, nocorpus.SeedCalls)
+	assert.Equal(t, int64(0), nocorpus.MeanSize())
+	assert.True(t, nocorpus.Newest.IsZero())
+}
@@ -2,9 +2,12 @@ package fuzz
 
 import (
 	"context"
+	"github.com/form3tech-oss/go-ci-fuzz/fuzz/corpusfile"
 	"github.com/stretchr/testify/assert"
 	"io/fs"
+	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 )
 
@@ -101,3 +104,231 @@ func TestCorpusDelete(t *testing.T) {
 
 	})
 }
+
+func TestFindCorpusEntry(t *testing.T) {
+	project := Project{Directory: "./testdata/corpus/multiple"}
+	ctx := context.Background()
+
+	t.Run("resolves entry of a discovered target", func(t *testing.T) {
+		paths, err := project.FindCorpusEntry(ctx, "FuzzSubTarget/0a7e5e215d8c088d4b9c4993d0189a07e81603fbdf64f2ca44738aa27159acef", "...")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"testdata/corpus/multiple/sub/testdata/fuzz/FuzzSubTarget/0a7e5e215d8c088d4b9c4993d0189a07e81603fbdf64f2ca44738aa27159acef",
+		}, paths)
+	})
+
+	t.Run("ignores corpora without target", func(t *testing.T) {
+		_, err := project.FindCorpusEntry(ctx, "FuzzNonExistingTarget/0a7e5e215d8c088d4b9c4993d0189a07e81603fbdf64f2ca44738aa27159acef", "...")
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		_, err := project.FindCorpusEntry(ctx, "FuzzTarget", "...")
+		assert.Error(t, err)
+	})
+}
+
+func TestCorpusImport(t *testing.T) {
+	ctx := context.Background()
+	setup := func(t *testing.T) Project {
+		tempDir := t.TempDir()
+		if err := copyDirectory(tempDir, "./testdata/corpus/import"); err != nil {
+			t.Fatal(err)
+		}
+		return Project{Directory: tempDir}
+	}
+
+	t.Run("imports raw files into []byte target", func(t *testing.T) {
+		project := setup(t)
+		target := Target{Name: "FuzzBytes", Package: "import", RootPackage: "import", Args: []string{"[]byte"}}
+
+		result, err := project.CorpusImport(ctx, target, "./testdata/corpus/import/raw")
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, ImportResult{Imported: 2, Skipped: 1}, result,
+			"duplicate raw files must be skipped, hidden files, go-fuzz side files and suppressions must be ignored")
+
+		corpusDir := filepath.Join(project.Directory, "testdata/fuzz/FuzzBytes")
+		files, err := listFilesRecursively(corpusDir)
+		if !assert.NoError(t, err) || !assert.Len(t, files, 2) {
+			return
+		}
+
+		var values []any
+		for _, file := range files {
+			vals, err := corpusfile.ReadFile(filepath.Join(corpusDir, file))
+			assert.NoError(t, err)
+			values = append(values, vals...)
+		}
+		assert.ElementsMatch(t, []any{[]byte("hello"), []byte{0x00, 0x01, 0xff}}, values)
+
+		result, err = project.CorpusImport(ctx, target, "./testdata/corpus/import/raw")
+		assert.NoError(t, err)
+		assert.Equal(t, ImportResult{Imported: 0, Skipped: 3}, result, "existing entries must be skipped")
+	})
+
+	t.Run("imports raw files into string target", func(t *testing.T) {
+		project := setup(t)
+		target := Target{Name: "FuzzString", Package: "import", RootPackage: "import", Args: []string{"string"}}
+
+		result, err := project.CorpusImport(ctx, target, "./testdata/corpus/import/raw")
+		assert.NoError(t, err)
+		assert.Equal(t, ImportResult{Imported: 2, Skipped: 1}, result)
+
+		vals, err := corpusfile.ReadFile(filepath.Join(project.Directory, "testdata/fuzz/FuzzString", corpusfile.Name([]byte("go test fuzz v1\nstring(\"hello\")\n"))))
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"hello"}, vals)
+	})
+
+	t.Run("refuses targets with multiple arguments", func(t *testing.T) {
+		project := setup(t)
+		target := Target{Name: "FuzzMultiple", Package: "import", RootPackage: "import", Args: []string{"string", "int"}}
+
+		_, err := project.CorpusImport(ctx, target, "./testdata/corpus/import/raw")
+		assert.Error(t, err)
+	})
+
+	t.Run("refuses targets with unknown arguments", func(t *testing.T) {
+		project := setup(t)
+		target := Target{Name: "FuzzBytes", Package: "import", RootPackage: "import", Warnings: []string{errUnknownArguments.Error()}}
+
+		_, err := project.CorpusImport(ctx, target, "./testdata/corpus/import/raw")
+		assert.ErrorContains(t, err, "arguments of import#FuzzBytes are unknown")
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		project := setup(t)
+		target := Target{Name: "FuzzBytes", Package: "import", RootPackage: "import", Args: []string{"[]byte"}}
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		result, err := project.CorpusImport(ctx, target, "./testdata/corpus/import/raw")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, ImportResult{}, result)
+	})
+}
+
+func TestCorpusExportRaw(t *testing.T) {
+	project := Project{Directory: "./testdata/corpus/export"}
+	ctx := context.Background()
+	tempDir := t.TempDir()
+
+	result, err := project.CorpusExportRaw(ctx, NewDirStore(tempDir), "...")
+	if !assert.NoError(t, err, "corpus export should not fail") {
+		return
+	}
+	assert.Equal(t, 2, result.Exported)
+	if assert.Len(t, result.Skipped, 1, "undecodable entries are skipped") {
+		assert.Contains(t, result.Skipped[0].Error(), "0000000000000000")
+	}
+
+	files, err := listFilesRecursively(tempDir)
+	if !assert.NoError(t, err, "listing tempDir should not fail") {
+		return
+	}
+	assert.ElementsMatch(t, files, []string{
+		"testdata/fuzz/FuzzBytes/04b5d8b5da5743c0",
+		"testdata/fuzz/FuzzMultiple/5bf3e3c464cbd6bc.json",
+	})
+
+	raw, err := os.ReadFile(filepath.Join(tempDir, "testdata/fuzz/FuzzBytes/04b5d8b5da5743c0"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0x01, 0xff}, raw)
+
+	doc, err := os.ReadFile(filepath.Join(tempDir, "testdata/fuzz/FuzzMultiple/5bf3e3c464cbd6bc.json"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"values": [{"type": "string", "value": "a", "length": 1}, {"type": "int", "value": 1}]}`, string(doc))
+}
+
+func TestCorpusMergeFrom(t *testing.T) {
+	t.Run("merges corpora from an archive", func(t *testing.T) {
+		ctx := context.Background()
+		archive := filepath.Join(t.TempDir(), "corpus.tar.gz")
+
+		store, err := OpenArchiveStore(archive)
+		if !assert.NoError(t, err) {
+			return
+		}
+		err = (&Project{Directory: "./testdata/corpus/multiple"}).CorpusExtractTo(ctx, store, "...")
+		if !assert.NoError(t, err) || !assert.NoError(t, store.Close()) {
+			return
+		}
+
+		tempDir := t.TempDir()
+		if err := copyDirectory(tempDir, "./testdata/corpus/multiple"); err != nil {
+			t.Fatal(err)
+		}
+		project := Project{Directory: tempDir}
+		if err := project.CorpusDelete(ctx, "..."); err != nil {
+			t.Fatal(err)
+		}
+
+		store, err = OpenArchiveStore(archive)
+		if !assert.NoError(t, err) {
+			return
+		}
+		_, err = project.CorpusMergeFrom(ctx, store, MergeOptions{}, "...")
+		assert.NoError(t, err)
+
+		files, err := listFilesRecursively(tempDir)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{
+			"go.mod",
+			"main_test.go",
+			"nocorpus/main_test.go",
+			"sub/main_test.go",
+			"testdata/fuzz/FuzzTarget/0a7e5e215d8c088d4b9c4993d0189a07e81603fbdf64f2ca44738aa27159acef",
+			"sub/testdata/fuzz/FuzzSubTarget/0a7e5e215d8c088d4b9c4993d0189a07e81603fbdf64f2ca44738aa27159acef",
+			"sub/testdata/fuzz/FuzzNonExistingTarget/0a7e5e215d8c088d4b9c4993d0189a07e81603fbdf64f2ca44738aa27159acef",
+		}, files)
+	})
+
+	t.Run("skips identical entries and reports conflicts", func(t *testing.T) {
+		ctx := context.Background()
+		tempDir := t.TempDir()
+		if err := copyDirectory(tempDir, "./testdata/corpus/multiple"); err != nil {
+			t.Fatal(err)
+		}
+		project := Project{Directory: tempDir}
+
+		external := NewDirStore(t.TempDir())
+		entry := "0a7e5e215d8c088d4b9c4993d0189a07e81603fbdf64f2ca44738aa27159acef"
+		for name, content := range map[string]string{
+			"testdata/fuzz/FuzzTarget/" + entry:        "go test fuzz v1\nstring(\"z\")\n",
+			"testdata/fuzz/FuzzTarget/new":             "go test fuzz v1\nstring(\"new\")\n",
+			"sub/testdata/fuzz/FuzzSubTarget/" + entry: "go test fuzz v1\nstring(\"conflict\")\n",
+		} {
+			if err := external.Write(ctx, name, []byte(content)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		results, err := project.CorpusMergeFrom(ctx, external, MergeOptions{}, "...")
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		summary := map[string]MergeResult{}
+		for _, result := range results {
+			summary[result.Target.Package] = MergeResult{Added: result.Added, Skipped: result.Skipped, Conflicts: result.Conflicts}
+		}
+		assert.Equal(t, map[string]MergeResult{
+			"multiple":          {Added: 1, Skipped: 1},
+			"multiple/sub":      {Conflicts: []string{entry}},
+			"multiple/nocorpus": {},
+		}, summary)
+
+		content, err := os.ReadFile(filepath.Join(tempDir, "sub/testdata/fuzz/FuzzSubTarget", entry))
+		assert.NoError(t, err)
+		assert.Equal(t, "go test fuzz v1\nstring(\"z\")\n", string(content), "conflicting entries must not be overwritten")
+
+		info, err := os.Stat(filepath.Join(tempDir, "testdata/fuzz/FuzzTarget/new"))
+		if assert.NoError(t, err) && runtime.GOOS != "windows" {
+			assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+		}
+
+		_, err = project.CorpusMergeFrom(ctx, external, MergeOptions{FailOnConflict: true}, "...")
+		assert.ErrorIs(t, err, ErrCorpusConflict)
+	})
+}
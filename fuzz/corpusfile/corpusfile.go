@@ -0,0 +1,439 @@
+// Package corpusfile reads and writes corpus entries in the "go test fuzz v1"
+// format used by the Go toolchain for files in testdata/fuzz/<Target> and the
+// fuzzing cache.
+//
+// The encoding mirrors the one implemented in the Go standard library
+// (internal/fuzz/encoding.go) so that entries written by this package are
+// accepted by 'go test' and vice versa.
+package corpusfile
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Version1 is the first line of every corpus file.
+const Version1 = "go test fuzz v1"
+
+// Marshal encodes vals into the corpus file format.
+// Supported types are string, []byte, bool, all integer and floating point
+// types; byte and rune are aliases of uint8 and int32 and are encoded as such.
+func Marshal(vals ...any) ([]byte, error) {
+	if len(vals) == 0 {
+		return nil, errors.New("must have at least one value to marshal")
+	}
+
+	b := bytes.NewBuffer([]byte(Version1 + "\n"))
+	for _, val := range vals {
+		switch t := val.(type) {
+		case int, int8, int16, int64, uint, uint16, uint32, uint64, bool:
+			fmt.Fprintf(b, "%T(%v)\n", t, t)
+		case float32:
+			// NaNs other than the canonical one are written as bits so that
+			// the exact value can be reproduced.
+			if math.IsNaN(float64(t)) && math.Float32bits(t) != math.Float32bits(float32(math.NaN())) {
+				fmt.Fprintf(b, "math.Float32frombits(0x%x)\n", math.Float32bits(t))
+			} else {
+				fmt.Fprintf(b, "%T(%v)\n", t, t)
+			}
+		case float64:
+			if math.IsNaN(t) && math.Float64bits(t) != math.Float64bits(math.NaN()) {
+				fmt.Fprintf(b, "math.Float64frombits(0x%x)\n", math.Float64bits(t))
+			} else {
+				fmt.Fprintf(b, "%T(%v)\n", t, t)
+			}
+		case string:
+			fmt.Fprintf(b, "string(%q)\n", t)
+		case rune:
+			// Only valid runes have a quoted representation, %q would turn
+			// anything else into the replacement character.
+			if utf8.ValidRune(t) {
+				fmt.Fprintf(b, "rune(%q)\n", t)
+			} else {
+				fmt.Fprintf(b, "int32(%v)\n", t)
+			}
+		case byte:
+			fmt.Fprintf(b, "byte(%q)\n", t)
+		case []byte:
+			fmt.Fprintf(b, "[]byte(%q)\n", t)
+		default:
+			return nil, fmt.Errorf("unsupported type: %T", t)
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// Unmarshal decodes the content of a corpus file into its values.
+func Unmarshal(b []byte) ([]any, error) {
+	if len(b) == 0 {
+		return nil, errors.New("cannot unmarshal empty corpus file")
+	}
+
+	lines := bytes.Split(b, []byte("\n"))
+	if len(lines) < 2 {
+		return nil, errors.New("must include version and at least one value")
+	}
+
+	version := strings.TrimSuffix(string(lines[0]), "\r")
+	if version != Version1 {
+		return nil, fmt.Errorf("unknown encoding version: %s", version)
+	}
+
+	var vals []any
+	for _, line := range lines[1:] {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		v, err := parseValue(line)
+		if err != nil {
+			return nil, fmt.Errorf("malformed line %q: %w", line, err)
+		}
+		vals = append(vals, v)
+	}
+
+	if len(vals) == 0 {
+		return nil, errors.New("must include at least one value")
+	}
+	return vals, nil
+}
+
+// ReadFile reads and decodes the corpus file at path.
+func ReadFile(path string) ([]any, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	vals, err := Unmarshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return vals, nil
+}
+
+// WriteFile encodes vals and writes them to dir under their content based
+// name, see Name. An existing entry with the same name is left untouched.
+// It returns the path of the entry and whether it has been created.
+func WriteFile(dir string, vals ...any) (string, bool, error) {
+	data, err := Marshal(vals...)
+	if err != nil {
+		return "", false, err
+	}
+
+	path := filepath.Join(dir, Name(data))
+	if _, err := os.Stat(path); err == nil {
+		return path, false, nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", false, err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		os.Remove(path)
+		return "", false, err
+	}
+	return path, true, nil
+}
+
+// Name returns the file name the Go toolchain gives to a corpus entry with
+// the given encoded content.
+func Name(data []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(data))[:16]
+}
+
+// TypeName returns the name of the type of v as it's written in corpus files,
+// uint8 and int32 are reported as such rather than byte and rune.
+func TypeName(v any) string {
+	if _, ok := v.([]byte); ok {
+		return "[]byte"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func parseValue(line []byte) (any, error) {
+	fs := token.NewFileSet()
+	expr, err := parser.ParseExprFrom(fs, "(corpus)", line, 0)
+	if err != nil {
+		return nil, err
+	}
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return nil, errors.New("expected call expression")
+	}
+	if len(call.Args) != 1 {
+		return nil, fmt.Errorf("expected call expression with 1 argument; got %d", len(call.Args))
+	}
+	arg := call.Args[0]
+
+	if arrayType, ok := call.Fun.(*ast.ArrayType); ok {
+		if arrayType.Len != nil {
+			return nil, errors.New("expected []byte or primitive type")
+		}
+		elt, ok := arrayType.Elt.(*ast.Ident)
+		if !ok || elt.Name != "byte" {
+			return nil, errors.New("expected []byte")
+		}
+		lit, ok := arg.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return nil, errors.New("string literal required for type []byte")
+		}
+		s, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(s), nil
+	}
+
+	var typ string
+	if selector, ok := call.Fun.(*ast.SelectorExpr); ok {
+		xIdent, ok := selector.X.(*ast.Ident)
+		if !ok || xIdent.Name != "math" {
+			return nil, errors.New("invalid selector type")
+		}
+		switch selector.Sel.Name {
+		case "Float64frombits":
+			typ = "float64-bits"
+		case "Float32frombits":
+			typ = "float32-bits"
+		default:
+			return nil, errors.New("invalid selector type")
+		}
+	} else {
+		ident, ok := call.Fun.(*ast.Ident)
+		if !ok {
+			return nil, errors.New("expected []byte or primitive type")
+		}
+		typ = ident.Name
+		if typ == "bool" {
+			return parseBool(arg)
+		}
+	}
+
+	val, kind, err := parseLiteral(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch typ {
+	case "string":
+		if kind != token.STRING {
+			return nil, errors.New("string literal value required for type string")
+		}
+		return strconv.Unquote(val)
+	case "byte", "rune":
+		if kind == token.INT {
+			if typ == "rune" {
+				return parseInt(val, typ)
+			}
+			return parseUint(val, typ)
+		}
+		if kind != token.CHAR {
+			return nil, errors.New("character literal required for byte/rune types")
+		}
+		if len(val) < 2 {
+			return nil, errors.New("malformed character literal, missing single quotes")
+		}
+		code, _, _, err := strconv.UnquoteChar(val[1:len(val)-1], '\'')
+		if err != nil {
+			return nil, err
+		}
+		if typ == "rune" {
+			return code, nil
+		}
+		if code >= 256 {
+			return nil, errors.New("can only encode single byte to a byte type")
+		}
+		return byte(code), nil
+	case "int", "int8", "int16", "int32", "int64":
+		if kind != token.INT {
+			return nil, errors.New("integer literal required for int types")
+		}
+		return parseInt(val, typ)
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		if kind != token.INT {
+			return nil, errors.New("integer literal required for uint types")
+		}
+		return parseUint(val, typ)
+	case "float32":
+		if kind != token.FLOAT && kind != token.INT {
+			return nil, errors.New("float or integer literal required for float32 type")
+		}
+		v, err := strconv.ParseFloat(val, 32)
+		return float32(v), err
+	case "float64":
+		if kind != token.FLOAT && kind != token.INT {
+			return nil, errors.New("float or integer literal required for float64 type")
+		}
+		return strconv.ParseFloat(val, 64)
+	case "float32-bits":
+		if kind != token.INT {
+			return nil, errors.New("integer literal required for math.Float32frombits type")
+		}
+		bits, err := strconv.ParseUint(val, 0, 32)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float32frombits(uint32(bits)), nil
+	case "float64-bits":
+		if kind != token.INT {
+			return nil, errors.New("integer literal required for math.Float64frombits type")
+		}
+		bits, err := strconv.ParseUint(val, 0, 64)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(bits), nil
+	default:
+		return nil, errors.New("expected []byte or primitive type")
+	}
+}
+
+func parseBool(arg ast.Expr) (any, error) {
+	id, ok := arg.(*ast.Ident)
+	if !ok {
+		return nil, errors.New("malformed bool")
+	}
+	switch id.Name {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	default:
+		return nil, errors.New("true or false required for type bool")
+	}
+}
+
+// parseLiteral returns the textual value of a literal argument along with its kind.
+// Negative numbers as well as the identifiers Inf and NaN are accepted.
+func parseLiteral(arg ast.Expr) (string, token.Token, error) {
+	if op, ok := arg.(*ast.UnaryExpr); ok {
+		switch lit := op.X.(type) {
+		case *ast.BasicLit:
+			if op.Op != token.SUB {
+				return "", token.ILLEGAL, fmt.Errorf("unsupported operation on int/float: %v", op.Op)
+			}
+			return op.Op.String() + lit.Value, lit.Kind, nil
+		case *ast.Ident:
+			if lit.Name != "Inf" {
+				return "", token.ILLEGAL, errors.New("expected operation on int or float type")
+			}
+			if op.Op == token.SUB {
+				return "-Inf", token.FLOAT, nil
+			}
+			return "+Inf", token.FLOAT, nil
+		default:
+			return "", token.ILLEGAL, errors.New("expected operation on int or float type")
+		}
+	}
+
+	switch lit := arg.(type) {
+	case *ast.BasicLit:
+		return lit.Value, lit.Kind, nil
+	case *ast.Ident:
+		if lit.Name != "NaN" {
+			return "", token.ILLEGAL, errors.New("literal value required for primitive type")
+		}
+		return "NaN", token.FLOAT, nil
+	default:
+		return "", token.ILLEGAL, errors.New("literal value required for primitive type")
+	}
+}
+
+func parseInt(val, typ string) (any, error) {
+	switch typ {
+	case "int":
+		// corpora generated on 64 bit platforms may contain values which
+		// overflow on 32 bit ones, they are wrapped just like Go does.
+		i, err := strconv.ParseInt(val, 0, 64)
+		return int(i), err
+	case "int8":
+		i, err := strconv.ParseInt(val, 0, 8)
+		return int8(i), err
+	case "int16":
+		i, err := strconv.ParseInt(val, 0, 16)
+		return int16(i), err
+	case "int32", "rune":
+		i, err := strconv.ParseInt(val, 0, 32)
+		return int32(i), err
+	default:
+		return strconv.ParseInt(val, 0, 64)
+	}
+}
+
+func parseUint(val, typ string) (any, error) {
+	switch typ {
+	case "uint":
+		i, err := strconv.ParseUint(val, 0, 64)
+		return uint(i), err
+	case "uint8", "byte":
+		i, err := strconv.ParseUint(val, 0, 8)
+		return uint8(i), err
+	case "uint16":
+		i, err := strconv.ParseUint(val, 0, 16)
+		return uint16(i), err
+	case "uint32":
+		i, err := strconv.ParseUint(val, 0, 32)
+		return uint32(i), err
+	default:
+		return strconv.ParseUint(val, 0, 64)
+	}
+}
+
+// Value describes a decoded corpus value for presentation, e.g. as JSON.
+// Byte slices are encoded as base64 by encoding/json and floating point values
+// which cannot be represented in JSON (NaN, ±Inf) are kept as strings.
+type Value struct {
+	Type   string `json:"type"`
+	Value  any    `json:"value"`
+	Length *int   `json:"length,omitempty"`
+}
+
+// Describe returns the presentation of v.
+func Describe(v any) Value {
+	desc := Value{Type: TypeName(v), Value: v}
+	switch t := v.(type) {
+	case string:
+		length := len(t)
+		desc.Length = &length
+	case []byte:
+		length := len(t)
+		desc.Length = &length
+	case float32:
+		if math.IsNaN(float64(t)) || math.IsInf(float64(t), 0) {
+			desc.Value = fmt.Sprint(t)
+		}
+	case float64:
+		if math.IsNaN(t) || math.IsInf(t, 0) {
+			desc.Value = fmt.Sprint(t)
+		}
+	}
+	return desc
+}
+
+// NonFinite returns the Go expression of a NaN or ±Inf floating point value described by Describe,
+// i.e. math.NaN(), math.Inf(1) or math.Inf(-1), and whether the value is one of them.
+func NonFinite(val Value) (string, bool) {
+	if val.Type != "float32" && val.Type != "float64" {
+		return "", false
+	}
+	switch val.Value {
+	case "NaN":
+		return "math.NaN()", true
+	case "+Inf":
+		return "math.Inf(1)", true
+	case "-Inf":
+		return "math.Inf(-1)", true
+	}
+	return "", false
+}
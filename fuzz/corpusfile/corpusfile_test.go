@@ -0,0 +1,188 @@
+package corpusfile
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		vals []any
+	}{
+		{name: "string", vals: []any{"", "z", "new\nline \"quoted\" \x00\xff"}},
+		{name: "bytes", vals: []any{[]byte{}, []byte("z"), []byte{0, 1, 2, 0xfe, 0xff}}},
+		{name: "bool", vals: []any{true, false}},
+		{name: "int", vals: []any{int(0), int(-1), int(math.MaxInt64), int(math.MinInt64)}},
+		{name: "int8", vals: []any{int8(math.MinInt8), int8(math.MaxInt8)}},
+		{name: "int16", vals: []any{int16(math.MinInt16), int16(math.MaxInt16)}},
+		{name: "int32", vals: []any{int32(math.MinInt32), int32(math.MaxInt32), int32(-1)}},
+		{name: "int64", vals: []any{int64(math.MinInt64), int64(math.MaxInt64)}},
+		{name: "uint", vals: []any{uint(0), uint(math.MaxUint64)}},
+		{name: "uint16", vals: []any{uint16(math.MaxUint16)}},
+		{name: "uint32", vals: []any{uint32(math.MaxUint32)}},
+		{name: "uint64", vals: []any{uint64(math.MaxUint64)}},
+		{name: "float32", vals: []any{float32(0), float32(-1.5), float32(math.MaxFloat32), float32(math.SmallestNonzeroFloat32), float32(math.Inf(1)), float32(math.Inf(-1))}},
+		{name: "float64", vals: []any{float64(0), -1.5, math.MaxFloat64, math.SmallestNonzeroFloat64, math.Inf(1), math.Inf(-1), math.Copysign(0, -1)}},
+		{name: "rune", vals: []any{'a', '☃', rune(0), rune(utf8MaxRune)}},
+		{name: "invalid rune", vals: []any{rune(-1), rune(0xD800), rune(utf8MaxRune + 1)}},
+		{name: "byte", vals: []any{byte(0), byte('a'), byte(0xff)}},
+		{name: "mixed", vals: []any{"a", []byte("b"), 1, true, 1.5, byte('c'), 'd'}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Marshal(tt.vals...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			vals, err := Unmarshal(data)
+			if !assert.NoError(t, err, "unmarshalling %q", data) {
+				return
+			}
+			assert.Equal(t, tt.vals, vals)
+
+			again, err := Marshal(vals...)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, string(data), string(again), "encoding must be stable")
+		})
+	}
+
+	t.Run("NaN", func(t *testing.T) {
+		weird32 := math.Float32frombits(0x7fc00001)
+		weird64 := math.Float64frombits(0x7ff8000000000002)
+
+		data, err := Marshal(float32(math.NaN()), math.NaN(), weird32, weird64)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, "go test fuzz v1\nfloat32(NaN)\nfloat64(NaN)\nmath.Float32frombits(0x7fc00001)\nmath.Float64frombits(0x7ff8000000000002)\n", string(data))
+
+		vals, err := Unmarshal(data)
+		if !assert.NoError(t, err) || !assert.Len(t, vals, 4) {
+			return
+		}
+		assert.Equal(t, math.Float32bits(float32(math.NaN())), math.Float32bits(vals[0].(float32)))
+		assert.Equal(t, math.Float64bits(math.NaN()), math.Float64bits(vals[1].(float64)))
+		assert.Equal(t, math.Float32bits(weird32), math.Float32bits(vals[2].(float32)))
+		assert.Equal(t, math.Float64bits(weird64), math.Float64bits(vals[3].(float64)))
+	})
+}
+
+const utf8MaxRune = '\U0010FFFF'
+
+func TestMarshal(t *testing.T) {
+	t.Run("matches go toolchain encoding", func(t *testing.T) {
+		data, err := Marshal("z", []byte("a\x00"), int(-1), uint8('b'), int32('c'), int32(-5), true, float64(1.5))
+		assert.NoError(t, err)
+		assert.Equal(t, `go test fuzz v1
+string("z")
+[]byte("a\x00")
+int(-1)
+byte('b')
+rune('c')
+int32(-5)
+bool(true)
+float64(1.5)
+`, string(data))
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		_, err := Marshal(struct{}{})
+		assert.Error(t, err)
+	})
+
+	t.Run("no values", func(t *testing.T) {
+		_, err := Marshal()
+		assert.Error(t, err)
+	})
+}
+
+func TestUnmarshal(t *testing.T) {
+	t.Run("accepts alternative spellings", func(t *testing.T) {
+		vals, err := Unmarshal([]byte("go test fuzz v1\r\nbyte(97)\nrune(98)\nuint8(1)\nint32(2)\nfloat64(3)\nint(0x10)\n\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, []any{byte('a'), 'b', uint8(1), int32(2), float64(3), 16}, vals)
+	})
+
+	invalid := map[string]string{
+		"empty":             "",
+		"no values":         "go test fuzz v1\n",
+		"unknown version":   "go test fuzz v2\nstring(\"a\")\n",
+		"not a call":        "go test fuzz v1\n\"a\"\n",
+		"unknown type":      "go test fuzz v1\ncomplex128(1)\n",
+		"array":             "go test fuzz v1\n[1]byte(\"a\")\n",
+		"overflow":          "go test fuzz v1\nint8(128)\n",
+		"negative unsigned": "go test fuzz v1\nuint(-1)\n",
+		"type mismatch":     "go test fuzz v1\nstring(1)\n",
+		"bad bool":          "go test fuzz v1\nbool(1)\n",
+		"wide byte":         "go test fuzz v1\nbyte('☃')\n",
+		"bad selector":      "go test fuzz v1\nmath.Sqrt(4)\n",
+		"too many args":     "go test fuzz v1\nint(1, 2)\n",
+	}
+	for name, content := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := Unmarshal([]byte(content))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	vals, err := ReadFile("../testdata/corpus/multiple/testdata/fuzz/FuzzTarget/0a7e5e215d8c088d4b9c4993d0189a07e81603fbdf64f2ca44738aa27159acef")
+	assert.NoError(t, err)
+	assert.Equal(t, []any{"z"}, vals)
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "testdata/fuzz/FuzzTarget")
+
+	path, created, err := WriteFile(dir, "z")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, created)
+	// same name as the entries generated by the Go toolchain in testdata
+	assert.Equal(t, filepath.Join(dir, "0a7e5e215d8c088d"), path)
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "go test fuzz v1\nstring(\"z\")\n", string(content))
+
+	_, created, err = WriteFile(dir, "z")
+	assert.NoError(t, err)
+	assert.False(t, created, "existing entries must not be rewritten")
+}
+
+func TestDescribe(t *testing.T) {
+	length := 1
+	assert.Equal(t, Value{Type: "string", Value: "z", Length: &length}, Describe("z"))
+	assert.Equal(t, Value{Type: "[]byte", Value: []byte("z"), Length: &length}, Describe([]byte("z")))
+	assert.Equal(t, Value{Type: "uint8", Value: byte('z')}, Describe(byte('z')))
+	assert.Equal(t, Value{Type: "float64", Value: "+Inf"}, Describe(math.Inf(1)))
+	assert.Equal(t, Value{Type: "float32", Value: "NaN"}, Describe(float32(math.NaN())))
+}
+
+func TestNonFinite(t *testing.T) {
+	for val, expected := range map[any]string{
+		math.NaN():            "math.NaN()",
+		float32(math.Inf(1)):  "math.Inf(1)",
+		math.Inf(-1):          "math.Inf(-1)",
+		float32(math.Inf(-1)): "math.Inf(-1)",
+	} {
+		expr, ok := NonFinite(Describe(val))
+		assert.True(t, ok, "%v is not finite", val)
+		assert.Equal(t, expected, expr)
+	}
+
+	for _, val := range []any{1.5, float32(0), "NaN", "+Inf"} {
+		_, ok := NonFinite(Describe(val))
+		assert.False(t, ok, "%v is finite", val)
+	}
+}
@@ -0,0 +1,188 @@
+package fuzz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// maxExcerptLines limits the length of output excerpts attached to errors.
+const maxExcerptLines = 30
+
+// CrashError means that the fuzz function failed, e.g. panicked or called t.Fatal. Output is the part of the output
+// describing the failure, i.e. the failure message and the stack of panics.
+type CrashError struct {
+	Input  *FailingInputError
+	Output string
+}
+
+func (e CrashError) Error() string {
+	return failureMessage("fuzz function failed", e.Input, e.Output)
+}
+
+func (e CrashError) Unwrap() error {
+	return unwrapInput(e.Input)
+}
+
+// BuildError means that the test binary could not be built or set up, e.g. because of compile errors.
+type BuildError struct {
+	Output string
+}
+
+func (e BuildError) Error() string {
+	return "building test binary failed:\n" + e.Output
+}
+
+// HangError means that a fuzzing worker hung or terminated unexpectedly, 'go test' saves the input it was running
+// as a failing input in that case.
+type HangError struct {
+	Input  *FailingInputError
+	Output string
+}
+
+func (e HangError) Error() string {
+	return failureMessage("fuzzing process hung or terminated unexpectedly", e.Input, e.Output)
+}
+
+func (e HangError) Unwrap() error {
+	return unwrapInput(e.Input)
+}
+
+// OOMError means that a fuzzing worker ran out of memory or was killed, e.g. by the OOM killer.
+type OOMError struct {
+	Input  *FailingInputError
+	Output string
+}
+
+func (e OOMError) Error() string {
+	return failureMessage("fuzzing process ran out of memory", e.Input, e.Output)
+}
+
+func (e OOMError) Unwrap() error {
+	return unwrapInput(e.Input)
+}
+
+// RaceError means that the race detector reported a data race.
+type RaceError struct {
+	Input  *FailingInputError
+	Output string
+}
+
+func (e RaceError) Error() string {
+	return failureMessage("data race detected", e.Input, e.Output)
+}
+
+func (e RaceError) Unwrap() error {
+	return unwrapInput(e.Input)
+}
+
+// InternalFuzzerError means that 'go test' failed without reporting a failing input, e.g. because TestMain exited.
+type InternalFuzzerError struct {
+	Output string
+}
+
+func (e InternalFuzzerError) Error() string {
+	return "fuzzing failed with an unexpected exit error:\n" + e.Output
+}
+
+func failureMessage(msg string, input *FailingInputError, output string) string {
+	if input != nil {
+		msg = fmt.Sprintf("%s, %s", msg, input)
+	}
+	return msg + ":\n" + output
+}
+
+func unwrapInput(input *FailingInputError) error {
+	if input == nil {
+		return nil
+	}
+	return *input
+}
+
+// classifyFailure returns a typed error describing why 'go test' failed based on its parsed output,
+// nil if the output doesn't match any known failure and input alone describes it.
+// Processes killed after ctx is cancelled are killed by go-ci-fuzz itself, the run is reported as interrupted.
+func classifyFailure(ctx context.Context, output *outputParser, input *FailingInputError) error {
+	killed := output.excerpt(failureKilled)
+	if killed != "" && ctx.Err() != nil {
+		return fmt.Errorf("%w: %w", ErrInterrupted, ctx.Err())
+	}
+	if excerpt := output.excerpt(FailureRace); excerpt != "" {
+		return RaceError{Input: input, Output: excerpt}
+	}
+	if excerpt := output.excerpt(FailureOOM); excerpt != "" {
+		return OOMError{Input: input, Output: excerpt}
+	}
+	if killed != "" {
+		return OOMError{Input: input, Output: killed}
+	}
+	if excerpt := output.excerpt(FailureHang); excerpt != "" {
+		return HangError{Input: input, Output: excerpt}
+	}
+	if input == nil && output.buildFailed {
+		return BuildError{Output: output.lastLines()}
+	}
+	if excerpt := output.excerpt(FailureCrash); excerpt != "" && input != nil {
+		return CrashError{Input: input, Output: excerpt}
+	}
+	return nil
+}
+
+// Kinds of failures returned by FailureKind.
+const (
+	FailureCrash    = "crash"
+	FailureBuild    = "build"
+	FailureHang     = "hang"
+	FailureOOM      = "oom"
+	FailureRace     = "race"
+	FailureInternal = "internal"
+)
+
+// FailureKind returns the kind of failure described by err returned by Project.Fuzz, empty if err is not a failure.
+func FailureKind(err error) string {
+	switch {
+	case errors.As(err, &BuildError{}):
+		return FailureBuild
+	case errors.As(err, &HangError{}):
+		return FailureHang
+	case errors.As(err, &OOMError{}):
+		return FailureOOM
+	case errors.As(err, &RaceError{}):
+		return FailureRace
+	case errors.As(err, &InternalFuzzerError{}):
+		return FailureInternal
+	case errors.As(err, &CrashError{}):
+		return FailureCrash
+	case errors.As(err, &FailingInputError{}):
+		return FailureCrash
+	default:
+		return ""
+	}
+}
+
+// FailureOutput returns the output excerpt attached to the failure described by err returned by Project.Fuzz,
+// e.g. the panic and its stack, empty if there is none.
+func FailureOutput(err error) string {
+	var crashErr CrashError
+	var raceErr RaceError
+	var oomErr OOMError
+	var hangErr HangError
+	var buildErr BuildError
+	var internalErr InternalFuzzerError
+	switch {
+	case errors.As(err, &raceErr):
+		return raceErr.Output
+	case errors.As(err, &oomErr):
+		return oomErr.Output
+	case errors.As(err, &hangErr):
+		return hangErr.Output
+	case errors.As(err, &crashErr):
+		return crashErr.Output
+	case errors.As(err, &buildErr):
+		return buildErr.Output
+	case errors.As(err, &internalErr):
+		return internalErr.Output
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,140 @@
+package fuzz
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+)
+
+func TestClassifyFailure(t *testing.T) {
+	for name, tt := range map[string]struct {
+		output string
+		kind   string
+		// excerpt must be contained in the error
+		excerpt string
+	}{
+		"hang": {
+			output: `fuzz: elapsed: 0s, gathering baseline coverage: 1/1 completed, now fuzzing with 8 workers
+--- FAIL: FuzzTarget (3.05s)
+    fuzzing process hung or terminated unexpectedly: exit status 2
+    Failing input written to testdata/fuzz/FuzzTarget/582528ddfad69eb5
+    To re-run:
+    go test -run=FuzzTarget/582528ddfad69eb5
+FAIL
+exit status 1
+FAIL	example	3.07s`,
+			kind:    FailureHang,
+			excerpt: "fuzzing process hung or terminated unexpectedly: exit status 2",
+		},
+		"out of memory": {
+			output: `fuzz: elapsed: 3s, execs: 1024 (341/sec), new interesting: 0 (total: 1)
+--- FAIL: FuzzTarget (4.12s)
+    fuzzing process hung or terminated unexpectedly: signal: killed
+    Failing input written to testdata/fuzz/FuzzTarget/582528ddfad69eb5
+    To re-run:
+    go test -run=FuzzTarget/582528ddfad69eb5
+FAIL`,
+			kind:    FailureOOM,
+			excerpt: "signal: killed",
+		},
+		"race": {
+			output: `==================
+WARNING: DATA RACE
+Write at 0x00c000012345 by goroutine 8:
+  example.FuzzTarget.func1()
+      /src/example/main_test.go:12 +0x44
+==================
+--- FAIL: FuzzTarget (0.02s)
+    testing.go:1465: race detected during execution of test
+    Failing input written to testdata/fuzz/FuzzTarget/582528ddfad69eb5
+    To re-run:
+    go test -run=FuzzTarget/582528ddfad69eb5
+FAIL`,
+			kind:    FailureRace,
+			excerpt: "WARNING: DATA RACE\nWrite at 0x00c000012345 by goroutine 8:\n  example.FuzzTarget.func1()\n      /src/example/main_test.go:12 +0x44\n==================",
+		},
+		"race while fuzzing": {
+			output: `--- FAIL: FuzzTarget (0.03s)
+    --- FAIL: FuzzTarget (0.00s)
+        testing.go:1865: race detected during execution of test
+    
+    Failing input written to testdata/fuzz/FuzzTarget/582528ddfad69eb5
+    To re-run:
+    go test -run=FuzzTarget/582528ddfad69eb5
+FAIL`,
+			kind:    FailureRace,
+			excerpt: "testing.go:1865: race detected during execution of test",
+		},
+		"build": {
+			output: `# example [example.test]
+./main_test.go:7:3: undefined: undefined
+FAIL	example [build failed]`,
+			kind:    FailureBuild,
+			excerpt: "./main_test.go:7:3: undefined: undefined",
+		},
+		"crash": {
+			output: `--- FAIL: FuzzTarget (0.02s)
+    --- FAIL: FuzzTarget (0.00s)
+        main_test.go:9: too long: "0000"
+    
+    Failing input written to testdata/fuzz/FuzzTarget/582528ddfad69eb5
+    To re-run:
+    go test -run=FuzzTarget/582528ddfad69eb5
+FAIL`,
+			kind:    FailureCrash,
+			excerpt: "--- FAIL: FuzzTarget (0.02s)\n    --- FAIL: FuzzTarget (0.00s)\n        main_test.go:9: too long: \"0000\"",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			output := newOutputParser("testdata/fuzz/FuzzTarget", nil)
+			stream := output.stream()
+			_, _ = io.WriteString(stream, tt.output)
+			stream.Close()
+
+			input, err := output.failingInput()
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			err = classifyFailure(context.Background(), output, input)
+			if err == nil && input != nil {
+				err = *input
+			}
+			assert.Equal(t, tt.kind, FailureKind(err))
+			assert.Contains(t, err.Error(), tt.excerpt)
+
+			if tt.kind != FailureBuild {
+				var inputErr FailingInputError
+				assert.ErrorAs(t, err, &inputErr, "failing input must be reachable through errors.As")
+				assert.Equal(t, "582528ddfad69eb5", inputErr.ID)
+			}
+		})
+	}
+}
+
+func TestClassifyFailureOfCancelledRun(t *testing.T) {
+	// workers killed once the grace period of an interruption is over
+	output := newOutputParser("testdata/fuzz/FuzzTarget", nil)
+	stream := output.stream()
+	_, _ = io.WriteString(stream, `fuzz: elapsed: 3s, execs: 1024 (341/sec), new interesting: 0 (total: 1)
+--- FAIL: FuzzTarget (33.12s)
+    fuzzing process hung or terminated unexpectedly: signal: killed
+    Failing input written to testdata/fuzz/FuzzTarget/582528ddfad69eb5
+    To re-run:
+    go test -run=FuzzTarget/582528ddfad69eb5
+FAIL
+signal: killed`)
+	stream.Close()
+
+	input, err := output.failingInput()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = classifyFailure(ctx, output, input)
+	assert.ErrorIs(t, err, ErrInterrupted)
+	assert.Empty(t, FailureKind(err), "a cancelled run is not an OOM")
+}
@@ -0,0 +1,78 @@
+package fuzz
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+)
+
+// processes are the commands started by runCommand which are still running.
+var processes = struct {
+	sync.Mutex
+	cmds map[*exec.Cmd]bool
+}{cmds: map[*exec.Cmd]bool{}}
+
+// runCommand runs cmd like cmd.Run and keeps track of it until it exits, so that it can be killed by NotifyContext.
+func runCommand(cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	processes.Lock()
+	processes.cmds[cmd] = true
+	processes.Unlock()
+	defer func() {
+		processes.Lock()
+		delete(processes.cmds, cmd)
+		processes.Unlock()
+	}()
+
+	return cmd.Wait()
+}
+
+// killProcesses kills all running commands started by runCommand along with their children.
+func killProcesses() {
+	processes.Lock()
+	defer processes.Unlock()
+	for cmd := range processes.cmds {
+		_ = killProcess(cmd)
+	}
+}
+
+// NotifyContext returns a copy of parent which is cancelled on the first of signals, e.g. SIGINT or SIGTERM
+// when a CI job times out, so that fuzzing stops gracefully. Commands run for the project, e.g. 'go test' and its
+// fuzzing workers, are not in the process group of the caller, so on the second signal they're killed
+// before exit is called with ExitInterrupted rather than being left behind. stop releases the signals.
+func NotifyContext(parent context.Context, exit func(code int), signals ...os.Signal) (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancel(parent)
+	received := make(chan os.Signal, 2)
+	signal.Notify(received, signals...)
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-received:
+			cancel()
+		case <-done:
+			return
+		}
+
+		select {
+		case <-received:
+			killProcesses()
+			exit(ExitInterrupted)
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			signal.Stop(received)
+			close(done)
+			cancel()
+		})
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !unix
+
+package fuzz
+
+import (
+	"os/exec"
+)
+
+// interruptOnCancel keeps the default behaviour of killing cmd when its context is cancelled,
+// interrupting process groups is not supported on this platform.
+func interruptOnCancel(cmd *exec.Cmd) {
+}
+
+// killProcess kills cmd.
+func killProcess(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
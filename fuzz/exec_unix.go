@@ -0,0 +1,23 @@
+//go:build unix
+
+package fuzz
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// interruptOnCancel makes cmd receive SIGINT instead of SIGKILL when its context is cancelled.
+// The signal is sent to the whole process group, as a terminal does on Ctrl+C, so that 'go test'
+// and its fuzzing workers stop gracefully and write failing inputs found so far.
+func interruptOnCancel(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGINT)
+	}
+}
+
+// killProcess kills the process group of cmd started with interruptOnCancel.
+func killProcess(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
@@ -0,0 +1,58 @@
+//go:build unix
+
+package fuzz
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNotifyContext(t *testing.T) {
+	exited := make(chan int, 1)
+	ctx, stop := NotifyContext(context.Background(), func(code int) { exited <- code }, syscall.SIGINT)
+	defer stop()
+
+	// the child ignores SIGINT like a 'go test' stuck while stopping, only the second signal stops it
+	cmd := exec.CommandContext(ctx, "sh", "-c", "trap '' INT; sleep 60")
+	interruptOnCancel(cmd)
+	result := make(chan error, 1)
+	go func() {
+		result <- runCommand(cmd)
+	}()
+	assert.Eventually(t, func() bool {
+		processes.Lock()
+		defer processes.Unlock()
+		return processes.cmds[cmd]
+	}, 5*time.Second, 10*time.Millisecond, "command must be tracked while running")
+
+	assert.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGINT))
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("first signal must cancel the context")
+	}
+	select {
+	case err := <-result:
+		t.Fatalf("command must survive the first signal, exited with %v", err)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	assert.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGINT))
+	select {
+	case code := <-exited:
+		assert.Equal(t, ExitInterrupted, code)
+	case <-time.After(5 * time.Second):
+		t.Fatal("second signal must exit")
+	}
+	select {
+	case err := <-result:
+		assert.Error(t, err, "command must be killed by the second signal")
+	case <-time.After(5 * time.Second):
+		t.Fatal("second signal must kill the process group of the command")
+	}
+}
@@ -0,0 +1,230 @@
+package fuzz
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Finding is a failure of a target deduplicated by its Signature.
+type Finding struct {
+	Signature string `json:"signature"`
+	// Target is the target as formatted by Target.String.
+	Target string `json:"target"`
+	Kind   string `json:"kind"`
+	// Commit is the commit the finding was last seen at, empty outside of git repositories.
+	Commit string `json:"commit,omitempty"`
+	// Input is the path of the copy of the last failing input, empty if the input was not saved, e.g. seeds added by f.Add.
+	Input     string    `json:"input,omitempty"`
+	Output    string    `json:"output,omitempty"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	// Count is the number of times the finding was seen.
+	Count int `json:"count"`
+	// Resolved is when the finding was marked as resolved, nil while it's open. A resolved finding seen again is reopened.
+	Resolved *time.Time `json:"resolved,omitempty"`
+}
+
+// States of a finding when it's recorded, see FindingStore.Record.
+const (
+	FindingNew = "new"
+	// FindingKnown means that the finding is open and was seen before.
+	FindingKnown = "known"
+	// FindingRegressed means that the finding was resolved and seen again.
+	FindingRegressed = "regressed"
+)
+
+// newFinding describes the failure of target returned by Project.Fuzz at commit, input is the path of the copy of
+// the failing input, if any.
+func newFinding(target Target, commit string, fuzzErr error, input string) Finding {
+	return Finding{
+		Signature: Signature(target, fuzzErr),
+		Target:    target.String(),
+		Kind:      FailureKind(fuzzErr),
+		Commit:    commit,
+		Input:     input,
+		Output:    FailureOutput(fuzzErr),
+		LastSeen:  time.Now().UTC(),
+	}
+}
+
+// printFinding prints the state of a recorded finding along with the failing input.
+func printFinding(out io.Writer, finding Finding, state string, input FailingInputError) {
+	switch state {
+	case FindingNew:
+		fmt.Fprintf(out, "go-ci-fuzz: new %s finding %s in %s, %s\n", finding.Kind, finding.Signature, finding.Target, input)
+	default:
+		fmt.Fprintf(out, "go-ci-fuzz: %s %s finding %s in %s seen %d times since %s, %s\n",
+			state, finding.Kind, finding.Signature, finding.Target, finding.Count, finding.FirstSeen.Format(time.RFC3339), input)
+	}
+}
+
+// FindingStore is a file of findings with one JSON object per line. Every time a finding is seen or resolved
+// its updated state is appended, so the last line of a signature describes its current state and the previous
+// lines its history.
+type FindingStore struct {
+	path string
+	// history of every signature, the last entry is the current state
+	history map[string][]Finding
+	// order of signatures by first appearance
+	order []string
+}
+
+// OpenFindingStore reads the findings stored at path, a missing file has no findings.
+func OpenFindingStore(path string) (*FindingStore, error) {
+	s := &FindingStore{path: path, history: map[string][]Finding{}}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var finding Finding
+		if err := json.Unmarshal(scanner.Bytes(), &finding); err != nil {
+			return nil, fmt.Errorf("cannot parse finding at %s:%d: %w", path, line, err)
+		}
+		s.set(finding)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read findings from %s: %w", path, err)
+	}
+	return s, nil
+}
+
+func (s *FindingStore) set(finding Finding) {
+	if _, ok := s.history[finding.Signature]; !ok {
+		s.order = append(s.order, finding.Signature)
+	}
+	s.history[finding.Signature] = append(s.history[finding.Signature], finding)
+}
+
+func (s *FindingStore) current(signature string) (Finding, bool) {
+	history, ok := s.history[signature]
+	if !ok {
+		return Finding{}, false
+	}
+	return history[len(history)-1], true
+}
+
+// Findings returns the current state of all findings in the order they were first seen.
+func (s *FindingStore) Findings() []Finding {
+	findings := make([]Finding, 0, len(s.order))
+	for _, signature := range s.order {
+		finding, _ := s.current(signature)
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// Get returns the finding with the given signature, false if there is none.
+func (s *FindingStore) Get(signature string) (Finding, bool) {
+	return s.current(signature)
+}
+
+// Lookup returns the finding whose signature starts with prefix, it fails if there is none or the prefix is ambiguous.
+func (s *FindingStore) Lookup(prefix string) (Finding, error) {
+	var matches []string
+	for _, signature := range s.order {
+		if strings.HasPrefix(signature, prefix) {
+			matches = append(matches, signature)
+		}
+	}
+	switch {
+	case prefix == "" || len(matches) == 0:
+		return Finding{}, fmt.Errorf("no finding with signature %q", prefix)
+	case len(matches) > 1:
+		return Finding{}, fmt.Errorf("signature %q is ambiguous, it matches %s", prefix, strings.Join(matches, ", "))
+	}
+	finding, _ := s.current(matches[0])
+	return finding, nil
+}
+
+// History returns all recorded states of the finding with the given signature, oldest first.
+func (s *FindingStore) History(signature string) []Finding {
+	return append([]Finding{}, s.history[signature]...)
+}
+
+// Record stores that finding was seen at finding.LastSeen and returns its updated state along with one of
+// the Finding* states. Known findings keep the time they were first seen and increment their count,
+// resolved ones are reopened.
+func (s *FindingStore) Record(finding Finding) (Finding, string, error) {
+	state := FindingNew
+	known, ok := s.current(finding.Signature)
+	if ok {
+		finding.FirstSeen = known.FirstSeen
+		finding.Count = known.Count + 1
+		state = FindingKnown
+		if known.Resolved != nil {
+			state = FindingRegressed
+		}
+	} else {
+		finding.FirstSeen = finding.LastSeen
+		finding.Count = 1
+	}
+
+	if err := s.append(finding); err != nil {
+		return Finding{}, "", err
+	}
+	s.set(finding)
+	return finding, state, nil
+}
+
+// Resolve marks the finding with the given signature as resolved at the given time.
+func (s *FindingStore) Resolve(signature string, at time.Time) (Finding, error) {
+	finding, ok := s.current(signature)
+	if !ok {
+		return Finding{}, fmt.Errorf("no finding with signature %q", signature)
+	}
+	finding.Resolved = &at
+
+	if err := s.append(finding); err != nil {
+		return Finding{}, err
+	}
+	s.set(finding)
+	return finding, nil
+}
+
+// Unfixed reports whether target has an open finding last seen at commit.
+func (s *FindingStore) Unfixed(target Target, commit string) bool {
+	for _, signature := range s.order {
+		finding, _ := s.current(signature)
+		if finding.Target == target.String() && finding.Commit == commit && finding.Resolved == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *FindingStore) append(finding Finding) error {
+	line, err := json.Marshal(finding)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("cannot create directory of findings %s: %w", s.path, err)
+	}
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("cannot open findings %s: %w", s.path, err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("cannot write findings %s: %w", s.path, err)
+	}
+	return file.Close()
+}
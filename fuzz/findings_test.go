@@ -0,0 +1,104 @@
+package fuzz
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFindingStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "findings.jsonl")
+	target := Target{Name: "FuzzTarget", Package: "example"}
+	first := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	store, err := OpenFindingStore(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Empty(t, store.Findings())
+
+	finding, state, err := store.Record(Finding{Signature: "a", Target: target.String(), Kind: FailureCrash, Commit: "c1", LastSeen: first})
+	assert.NoError(t, err)
+	assert.Equal(t, FindingNew, state)
+	assert.Equal(t, 1, finding.Count)
+	assert.Equal(t, first, finding.FirstSeen)
+
+	_, _, err = store.Record(Finding{Signature: "b", Target: "example#FuzzOther", Kind: FailureHang, Commit: "c1", LastSeen: first})
+	assert.NoError(t, err)
+
+	finding, state, err = store.Record(Finding{Signature: "a", Target: target.String(), Kind: FailureCrash, Commit: "c2", Input: "inputs/a", LastSeen: first.Add(time.Hour)})
+	assert.NoError(t, err)
+	assert.Equal(t, FindingKnown, state)
+	assert.Equal(t, 2, finding.Count)
+	assert.Equal(t, first, finding.FirstSeen)
+	assert.Equal(t, first.Add(time.Hour), finding.LastSeen)
+
+	assert.True(t, store.Unfixed(target, "c2"))
+	assert.False(t, store.Unfixed(target, "c1"), "the finding was seen at a later commit")
+	assert.False(t, store.Unfixed(target, "c3"))
+
+	t.Run("reopened", func(t *testing.T) {
+		reopened, err := OpenFindingStore(path)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, store.Findings(), reopened.Findings())
+		assert.Equal(t, []string{"a", "b"}, []string{reopened.Findings()[0].Signature, reopened.Findings()[1].Signature})
+
+		got, ok := reopened.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, "inputs/a", got.Input)
+	})
+
+	t.Run("resolved", func(t *testing.T) {
+		resolved, err := store.Resolve("a", first.Add(2*time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, first.Add(2*time.Hour), *resolved.Resolved)
+		assert.False(t, store.Unfixed(target, "c2"), "resolved findings are not unfixed")
+
+		finding, state, err := store.Record(Finding{Signature: "a", Target: target.String(), Kind: FailureCrash, Commit: "c3", LastSeen: first.Add(3 * time.Hour)})
+		assert.NoError(t, err)
+		assert.Equal(t, FindingRegressed, state)
+		assert.Nil(t, finding.Resolved)
+		assert.Equal(t, 3, finding.Count)
+		assert.True(t, store.Unfixed(target, "c3"))
+
+		reopened, err := OpenFindingStore(path)
+		if !assert.NoError(t, err) {
+			return
+		}
+		history := reopened.History("a")
+		if assert.Len(t, history, 4) {
+			assert.Equal(t, []string{"c1", "c2", "c2", "c3"}, []string{history[0].Commit, history[1].Commit, history[2].Commit, history[3].Commit})
+			assert.NotNil(t, history[2].Resolved)
+		}
+
+		_, err = store.Resolve("missing", first)
+		assert.Error(t, err)
+	})
+
+	t.Run("lookup", func(t *testing.T) {
+		_, _, err := store.Record(Finding{Signature: "ab", Target: target.String(), Kind: FailureCrash, LastSeen: first})
+		assert.NoError(t, err)
+
+		finding, err := store.Lookup("b")
+		assert.NoError(t, err)
+		assert.Equal(t, "b", finding.Signature)
+		finding, err = store.Lookup("ab")
+		assert.NoError(t, err)
+		assert.Equal(t, "ab", finding.Signature)
+		_, err = store.Lookup("a")
+		assert.ErrorContains(t, err, "ambiguous")
+		_, err = store.Lookup("c")
+		assert.Error(t, err)
+	})
+
+	t.Run("corrupted", func(t *testing.T) {
+		corrupted := filepath.Join(t.TempDir(), "findings.jsonl")
+		assert.NoError(t, os.WriteFile(corrupted, []byte("{\"signature\":\"a\"}\nnot json\n"), 0644))
+		_, err := OpenFindingStore(corrupted)
+		assert.ErrorContains(t, err, "findings.jsonl:2")
+	})
+}
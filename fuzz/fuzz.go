@@ -1,8 +1,6 @@
 package fuzz
 
 import (
-	"bufio"
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -20,9 +18,19 @@ var (
 	failingSeedInputRegex = regexp.MustCompile(`^\s*failure while testing seed corpus entry: Fuzz([a-zA-Z0-9_]+)/([a-zA-Z0-9#]+)`)
 )
 
+// ErrInterrupted is returned by Fuzz when its context is cancelled, e.g. on SIGINT or SIGTERM.
+var ErrInterrupted = errors.New("fuzzing interrupted")
+
+// interruptGracePeriod is how long an interrupted 'go test' may take to exit before it's killed.
+const interruptGracePeriod = 30 * time.Second
+
 type Project struct {
 	Directory string
 	Quiet     bool
+	// Build is applied to discovery, fuzzing and re-runs of failing inputs.
+	Build BuildOptions
+	// Progress is called with fuzzing statuses periodically reported by 'go test' while a target is fuzzed.
+	Progress func(target Target, progress Progress)
 }
 
 type FailingInputError struct {
@@ -59,76 +67,130 @@ func (p *Project) Fuzz(ctx context.Context, target Target, d time.Duration) erro
 		"-test.run=^$",
 		"-test.fuzz=^" + target.Name + "$",
 		"-test.fuzztime=" + d.String(),
-		target.Package,
 	}
+	args = append(args, p.Build.flags()...)
+	args = append(args, target.Package)
 
-	goBin, err := exec.LookPath("go")
+	cmd, err := p.goCommand(ctx, args...)
 	if err != nil {
-		return errors.New("go is not installed")
+		return err
 	}
-	cmd := exec.CommandContext(ctx, goBin, args...)
-	if p.Directory != "" {
-		cmd.Dir = p.Directory
+	interruptOnCancel(cmd)
+	cmd.WaitDelay = interruptGracePeriod
+
+	corpusDirectory, err := p.relCorpusDir(target)
+	if err != nil {
+		return fmt.Errorf("cannot locate relative corpus directory: %w", err)
 	}
 
-	var stdout bytes.Buffer
+	// both streams are parsed, build errors and race reports are written to stderr
+	var onProgress func(Progress)
+	if p.Progress != nil {
+		onProgress = func(progress Progress) {
+			p.Progress(target, progress)
+		}
+	}
+	output := newOutputParser(corpusDirectory, onProgress)
+	stdout, stderr := output.stream(), output.stream()
 	if !p.Quiet {
-		cmd.Stdout = io.MultiWriter(os.Stdout, &stdout)
+		cmd.Stdout = io.MultiWriter(os.Stdout, stdout)
 	} else {
-		cmd.Stdout = &stdout
+		cmd.Stdout = stdout
 	}
-	cmd.Stderr = os.Stderr
-
-	err = cmd.Run()
-	if err == nil {
+	cmd.Stderr = io.MultiWriter(os.Stderr, stderr)
+
+	runErr := runCommand(cmd)
+	stdout.Close()
+	stderr.Close()
+	if runErr == nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("%w: %w", ErrInterrupted, ctx.Err())
+		}
 		return nil
 	}
 
+	// an interrupted run may still have written a failing input, look for it before reporting the interruption
 	var exitErr *exec.ExitError
-	if !errors.As(err, &exitErr) {
-		return fmt.Errorf("fuzzing failed with an unexpected error: %w", err)
+	if !errors.As(runErr, &exitErr) && ctx.Err() == nil {
+		return fmt.Errorf("fuzzing failed with an unexpected error: %w", runErr)
 	}
 
-	scanner := bufio.NewScanner(&stdout)
-	corpusDirectory, err := p.relCorpusDir(target)
+	input, err := output.failingInput()
 	if err != nil {
-		return fmt.Errorf("cannot locate relative corpus directory: %w", err)
+		return err
+	}
+
+	if input == nil && ctx.Err() != nil {
+		return fmt.Errorf("%w: %w", ErrInterrupted, ctx.Err())
 	}
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		// For newly discovered inputs the CLI outputs the following:
-		// > Failing input written to testdata/fuzz/FuzzTarget/0a7e5e215d8c088d4b9c4993d0189a07e81603fbdf64f2ca44738aa27159acef
-		// > To re-run:
-		// > go test -run=FuzzTarget/0a7e5e215d8c088d4b9c4993d0189a07e81603fbdf64f2ca44738aa27159acef
-		// we match against the last line and extract the Test ID from it
-		if matches := failingInputRegex.FindStringSubmatch(line); matches != nil {
-			if len(matches) != 3 {
-				return fmt.Errorf("parsing fuzzing output failed, matched %q, but found %d submatches, expected 2", line, len(matches))
-			}
-
-			id := matches[2]
-			return FailingInputError{ID: id, File: filepath.Join(corpusDirectory, id)}
+	failure := classifyFailure(ctx, output, input)
+	var raceErr RaceError
+	if errors.As(failure, &raceErr) && input != nil && !strings.Contains(raceErr.Output, raceReportHeader) {
+		// fuzzing workers don't forward race reports, the input is re-run to capture the report with both stacks
+		if report := p.raceReport(ctx, target, *input); report != "" {
+			raceErr.Output = report
+			failure = raceErr
 		}
+	}
+	if failure != nil {
+		return failure
+	}
+	if input != nil {
+		return *input
+	}
+	return InternalFuzzerError{Output: output.lastLines()}
+}
+
+// raceReport runs the failing input of target and returns the race report it triggers, empty if there is none.
+func (p *Project) raceReport(ctx context.Context, target Target, input FailingInputError) string {
+	_, out, err := p.runInput(ctx, target, input)
+	if err != nil {
+		return ""
+	}
 
-		// For inputs already in the corpus we get
-		// > failure while testing seed corpus entry: FuzzTarget/seed#0
-		// for seed corpus entries added by f.Add() OR
-		// > failure while testing seed corpus entry: FuzzTarget/0a7e5e215d8c088d4b9c4993d0189a07e81603fbdf64f2ca44738aa27159acef
-		// for seed corpus stored in files in ./testdata directory
-		if matches := failingSeedInputRegex.FindStringSubmatch(line); matches != nil {
-			if len(matches) != 3 {
-				return fmt.Errorf("parsing seed corpus fuzzing output failed, matched %q, but found %d submatches, expected 2", line, len(matches))
-			}
-			id := matches[2]
-			if strings.HasPrefix(id, "seed#") {
-				return FailingInputError{ID: id, Seed: true}
-			} else {
-				return FailingInputError{ID: id, File: filepath.Join(corpusDirectory, id), Seed: true}
-			}
+	output := newOutputParser("", nil)
+	stream := output.stream()
+	_, _ = io.WriteString(stream, out)
+	stream.Close()
+	report := output.excerpt(FailureRace)
+	if !strings.Contains(report, raceReportHeader) {
+		return ""
+	}
+	return report
+}
+
+// parseFailingInput parses the failing input reported in a line of 'go test' output, nil if there is none.
+func parseFailingInput(corpusDirectory string, line string) (*FailingInputError, error) {
+	// For newly discovered inputs the CLI outputs the following:
+	// > Failing input written to testdata/fuzz/FuzzTarget/0a7e5e215d8c088d4b9c4993d0189a07e81603fbdf64f2ca44738aa27159acef
+	// > To re-run:
+	// > go test -run=FuzzTarget/0a7e5e215d8c088d4b9c4993d0189a07e81603fbdf64f2ca44738aa27159acef
+	// we match against the last line and extract the Test ID from it
+	if matches := failingInputRegex.FindStringSubmatch(line); matches != nil {
+		if len(matches) != 3 {
+			return nil, fmt.Errorf("parsing fuzzing output failed, matched %q, but found %d submatches, expected 2", line, len(matches))
 		}
 
+		id := matches[2]
+		return &FailingInputError{ID: id, File: filepath.Join(corpusDirectory, id)}, nil
+	}
+
+	// For inputs already in the corpus we get
+	// > failure while testing seed corpus entry: FuzzTarget/seed#0
+	// for seed corpus entries added by f.Add() OR
+	// > failure while testing seed corpus entry: FuzzTarget/0a7e5e215d8c088d4b9c4993d0189a07e81603fbdf64f2ca44738aa27159acef
+	// for seed corpus stored in files in ./testdata directory
+	if matches := failingSeedInputRegex.FindStringSubmatch(line); matches != nil {
+		if len(matches) != 3 {
+			return nil, fmt.Errorf("parsing seed corpus fuzzing output failed, matched %q, but found %d submatches, expected 2", line, len(matches))
+		}
+		id := matches[2]
+		if strings.HasPrefix(id, "seed#") {
+			return &FailingInputError{ID: id, Seed: true}, nil
+		} else {
+			return &FailingInputError{ID: id, File: filepath.Join(corpusDirectory, id), Seed: true}, nil
+		}
 	}
 
-	return fmt.Errorf("fuzzing failed with an unexpected exit error: %w", err)
+	return nil, nil
 }
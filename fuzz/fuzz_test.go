@@ -75,4 +75,121 @@ func TestFuzz(t *testing.T) {
 
 		assert.NoError(t, err)
 	})
+
+	t.Run("build options", func(t *testing.T) {
+		ctx := context.Background()
+		p := Project{Directory: "./testdata/discovertags", Quiet: true, Build: BuildOptions{
+			Tags: []string{"integration"},
+			Env:  []string{"DISCOVERTAGS_FAIL=1"},
+		}}
+
+		err := p.Fuzz(ctx, Target{
+			Name:        "FuzzIntegration",
+			Package:     "discovertags",
+			RootPackage: "discovertags",
+		}, 5*time.Second)
+
+		var inputErr FailingInputError
+		assert.ErrorAs(t, err, &inputErr)
+		assert.True(t, inputErr.Seed, "the seed fails with the environment of the build options")
+	})
+
+	t.Run("reports progress", func(t *testing.T) {
+		ctx := context.Background()
+		var progress []Progress
+		p := Project{Directory: "./testdata/fuzzing/nofindings", Quiet: true, Progress: func(target Target, p Progress) {
+			assert.Equal(t, "FuzzTarget", target.Name)
+			progress = append(progress, p)
+		}}
+
+		err := p.Fuzz(ctx, Target{
+			Name:        "FuzzTarget",
+			Package:     "nofindings",
+			RootPackage: "nofindings",
+		}, 5*time.Second)
+
+		assert.NoError(t, err)
+		if assert.NotEmpty(t, progress, "fuzzing status is reported every 3s") {
+			assert.Greater(t, progress[len(progress)-1].Execs, int64(0))
+		}
+	})
+
+	t.Run("interrupted", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		p := Project{Directory: "./testdata/fuzzing/nofindings", Quiet: true}
+
+		time.AfterFunc(5*time.Second, cancel)
+		start := time.Now()
+		err := p.Fuzz(ctx, Target{
+			Name:        "FuzzTarget",
+			Package:     "nofindings",
+			RootPackage: "nofindings",
+		}, 10*time.Minute)
+
+		assert.ErrorIs(t, err, ErrInterrupted)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, time.Since(start), time.Minute, "fuzzing must stop once interrupted")
+	})
+
+	t.Run("build failure", func(t *testing.T) {
+		ctx := context.Background()
+		p := Project{Directory: "./testdata/fuzzing/buildfailure", Quiet: true}
+
+		err := p.Fuzz(ctx, Target{
+			Name:        "FuzzTarget",
+			Package:     "buildfailure",
+			RootPackage: "buildfailure",
+		}, 5*time.Second)
+
+		var buildErr BuildError
+		if assert.ErrorAs(t, err, &buildErr) {
+			assert.Contains(t, buildErr.Output, "undefined: undefined")
+		}
+	})
+
+	t.Run("TestMain failure", func(t *testing.T) {
+		ctx := context.Background()
+		p := Project{Directory: "./testdata/fuzzing/testmain", Quiet: true}
+
+		err := p.Fuzz(ctx, Target{
+			Name:        "FuzzTarget",
+			Package:     "testmain",
+			RootPackage: "testmain",
+		}, 5*time.Second)
+
+		var internalErr InternalFuzzerError
+		if assert.ErrorAs(t, err, &internalErr) {
+			assert.Contains(t, internalErr.Output, "FAIL\ttestmain")
+		}
+	})
+
+	t.Run("data race", func(t *testing.T) {
+		ctx := context.Background()
+		p := Project{Directory: "./testdata/fuzzing/race", Quiet: true, Build: BuildOptions{Race: true}}
+
+		removeTestData := func() {
+			if err := os.RemoveAll(filepath.Join(p.Directory, "testdata")); err != nil {
+				t.Fatal("removing old testdata failed", err)
+			}
+		}
+		removeTestData()
+		t.Cleanup(removeTestData)
+
+		err := p.Fuzz(ctx, Target{
+			Name:        "FuzzTarget",
+			Package:     "race",
+			RootPackage: "race",
+		}, 1*time.Minute)
+
+		var raceErr RaceError
+		if !assert.ErrorAs(t, err, &raceErr) || !assert.NotNil(t, raceErr.Input) {
+			return
+		}
+		assert.Equal(t, FailureRace, FailureKind(err))
+		assert.FileExists(t, filepath.Join(p.Directory, raceErr.Input.File))
+		assert.True(t, strings.HasPrefix(raceErr.Output, "WARNING: DATA RACE"), raceErr.Output)
+		assert.Contains(t, raceErr.Output, "Previous write at", "both accesses must be reported")
+		assert.Contains(t, raceErr.Output, "race.increment()")
+		assert.Contains(t, raceErr.Output, "race.FuzzTarget.func1()")
+	})
 }
@@ -0,0 +1,148 @@
+package fuzz
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/types"
+)
+
+var supportedArguments = map[string]bool{
+	"string":  true,
+	"[]byte":  true,
+	"[]uint8": true,
+	"bool":    true,
+	"byte":    true,
+	"rune":    true,
+	"int":     true,
+	"int8":    true,
+	"int16":   true,
+	"int32":   true,
+	"int64":   true,
+	"uint":    true,
+	"uint8":   true,
+	"uint16":  true,
+	"uint32":  true,
+	"uint64":  true,
+	"float32": true,
+	"float64": true,
+}
+
+// isSupportedArgument reports whether typ can be used as a fuzzing argument.
+// Named types are reported as unsupported, even if their underlying type is allowed, because they cannot be resolved without type-checking.
+func isSupportedArgument(typ string) bool {
+	return supportedArguments[typ]
+}
+
+// isFuzzTarget reports whether fn has the signature of a fuzz target, i.e. func(*testing.F).
+func isFuzzTarget(fn *ast.FuncDecl) bool {
+	params := fn.Type.Params.List
+	if len(params) != 1 || len(params[0].Names) > 1 || fn.Type.Results != nil {
+		return false
+	}
+
+	star, ok := params[0].Type.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+
+	switch typ := star.X.(type) {
+	case *ast.SelectorExpr:
+		return typ.Sel.Name == "F"
+	case *ast.Ident:
+		// dot-imported testing package
+		return typ.Name == "F"
+	default:
+		return false
+	}
+}
+
+// errUnknownArguments means that the function passed to f.Fuzz is neither a literal nor a function declared in the test
+// files of the package, e.g. a method value, so its arguments cannot be detected without type-checking.
+var errUnknownArguments = errors.New("fuzz function is not a literal, arguments unknown")
+
+// fuzzArguments looks for the f.Fuzz(func(t *testing.T, ...)) call in the body of fn and returns the types of its
+// fuzzing arguments. Functions passed by name are looked up in funcs, the functions declared in the package of fn.
+func fuzzArguments(fn *ast.FuncDecl, funcs map[string]*ast.FuncType) ([]string, error) {
+	if fn.Body == nil || len(fn.Type.Params.List) != 1 || len(fn.Type.Params.List[0].Names) != 1 {
+		return nil, errors.New("no f.Fuzz(func(t *testing.T, ...)) call found")
+	}
+	f := fn.Type.Params.List[0].Names[0].Name
+
+	var fuzzArg ast.Expr
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if fuzzArg != nil {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Fuzz" {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); !ok || x.Name != f {
+			return true
+		}
+		fuzzArg = call.Args[0]
+		return false
+	})
+
+	var fuzzFunc *ast.FuncType
+	switch arg := fuzzArg.(type) {
+	case nil:
+		return nil, fmt.Errorf("no %s.Fuzz(func(t *testing.T, ...)) call found", f)
+	case *ast.FuncLit:
+		fuzzFunc = arg.Type
+	case *ast.Ident:
+		fuzzFunc = funcs[arg.Name]
+	}
+	if fuzzFunc == nil {
+		return nil, errUnknownArguments
+	}
+
+	var args []string
+	for _, param := range fuzzFunc.Params.List {
+		typ := types.ExprString(param.Type)
+		// unnamed parameters still count as one argument
+		count := len(param.Names)
+		if count == 0 {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			args = append(args, typ)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil, errors.New("fuzz function must accept *testing.T as its first parameter")
+	}
+	return args[1:], nil
+}
+
+// fuzzSeeds counts the f.Add calls in the body of fn. Calls in loops or helper functions are counted once
+// or not at all, so the number is a lower bound of the seeds added at runtime.
+func fuzzSeeds(fn *ast.FuncDecl) int {
+	if fn.Body == nil || len(fn.Type.Params.List) != 1 || len(fn.Type.Params.List[0].Names) != 1 {
+		return 0
+	}
+	f := fn.Type.Params.List[0].Names[0].Name
+
+	seeds := 0
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Add" {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); ok && x.Name == f {
+			seeds++
+		}
+		return true
+	})
+	return seeds
+}
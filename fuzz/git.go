@@ -0,0 +1,44 @@
+package fuzz
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// git runs a git command in the project directory and returns its trimmed output.
+func (p *Project) git(ctx context.Context, args ...string) (string, error) {
+	gitBin, err := exec.LookPath("git")
+	if err != nil {
+		return "", errors.New("git is not installed")
+	}
+	cmd := exec.CommandContext(ctx, gitBin, args...)
+	if p.Directory != "" {
+		cmd.Dir = p.Directory
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// head returns the commit checked out in the project.
+func (p *Project) head(ctx context.Context) (string, error) {
+	return p.git(ctx, "rev-parse", "HEAD")
+}
+
+// pull fetches ref from remote and checks it out detached, local branches are left untouched.
+func (p *Project) pull(ctx context.Context, remote, ref string) error {
+	if _, err := p.git(ctx, "fetch", "--quiet", remote, ref); err != nil {
+		return err
+	}
+	_, err := p.git(ctx, "checkout", "--quiet", "--detach", "FETCH_HEAD")
+	return err
+}
@@ -0,0 +1,148 @@
+package fuzz
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics collects the state of runs and serves it in the Prometheus text format, e.g. for long-running sessions.
+// It's safe for concurrent use, statuses are recorded while the handler serves scrapes.
+type Metrics struct {
+	mu      sync.Mutex
+	targets map[string]*targetMetrics
+	current string
+	// budgetEnd is when the current run is expected to finish, zero if no run is in progress.
+	budgetEnd time.Time
+	now       func() time.Time
+}
+
+type targetMetrics struct {
+	// execs of previous runs of the target
+	execsBase int64
+	progress  Progress
+	findings  int
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{targets: map[string]*targetMetrics{}, now: time.Now}
+}
+
+func (m *Metrics) target(target Target) *targetMetrics {
+	name := target.String()
+	t, ok := m.targets[name]
+	if !ok {
+		t = &targetMetrics{}
+		m.targets[name] = t
+	}
+	return t
+}
+
+// startTarget records that target is being fuzzed and the run is expected to finish at budgetEnd.
+func (m *Metrics) startTarget(target Target, budgetEnd time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.target(target)
+	m.current = target.String()
+	m.budgetEnd = budgetEnd
+}
+
+// startFuzzing records that a new 'go test' process fuzzes target, e.g. after a suppressed failure.
+// The process reports its execs from zero, so the execs reported so far are kept as the base of the counter.
+func (m *Metrics) startFuzzing(target Target) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t := m.target(target)
+	t.execsBase += t.progress.Execs
+	t.progress = Progress{}
+}
+
+func (m *Metrics) progress(target Target, progress Progress) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.target(target).progress = progress
+}
+
+func (m *Metrics) finding(target Target) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.target(target).findings++
+}
+
+// done records that the run finished.
+func (m *Metrics) done() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.current = ""
+	m.budgetEnd = time.Time{}
+}
+
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// WriteTo writes all metrics in the Prometheus text format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.targets))
+	for name := range m.targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	metric := func(name, typ, help string, value func(t *targetMetrics) float64) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+		for _, target := range names {
+			fmt.Fprintf(&b, "%s{target=\"%s\"} %g\n", name, escapeLabel(target), value(m.targets[target]))
+		}
+	}
+
+	metric("go_ci_fuzz_execs_total", "counter", "Executions of the fuzz function.", func(t *targetMetrics) float64 {
+		return float64(t.execsBase + t.progress.Execs)
+	})
+	metric("go_ci_fuzz_execs_per_second", "gauge", "Executions per second reported by the last fuzzing status.", func(t *targetMetrics) float64 {
+		return float64(t.progress.ExecsPerSec)
+	})
+	metric("go_ci_fuzz_corpus_size", "gauge", "Size of the corpus including seeds reported by the last fuzzing status.", func(t *targetMetrics) float64 {
+		return float64(t.progress.Total)
+	})
+	metric("go_ci_fuzz_new_interesting", "gauge", "Inputs added to the cached corpus during the last run of the target.", func(t *targetMetrics) float64 {
+		return float64(t.progress.NewInteresting)
+	})
+	metric("go_ci_fuzz_findings_total", "counter", "Failing inputs found.", func(t *targetMetrics) float64 {
+		return float64(t.findings)
+	})
+	current := m.current
+	metric("go_ci_fuzz_current_target", "gauge", "1 for the target being fuzzed, 0 for others.", func(t *targetMetrics) float64 {
+		if current != "" && t == m.targets[current] {
+			return 1
+		}
+		return 0
+	})
+
+	remaining := time.Duration(0)
+	if !m.budgetEnd.IsZero() {
+		remaining = max(m.budgetEnd.Sub(m.now()), 0)
+	}
+	fmt.Fprintf(&b, "# HELP go_ci_fuzz_remaining_budget_seconds Time left until the current run is expected to finish.\n")
+	fmt.Fprintf(&b, "# TYPE go_ci_fuzz_remaining_budget_seconds gauge\n")
+	fmt.Fprintf(&b, "go_ci_fuzz_remaining_budget_seconds %g\n", remaining.Seconds())
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// escapeLabel escapes a label value as required by the Prometheus text format.
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,71 @@
+package fuzz
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	metrics := NewMetrics()
+	metrics.now = func() time.Time { return now }
+
+	a := Target{Name: "FuzzA", Package: "example"}
+	b := Target{Name: "FuzzB", Package: `example/"quoted"`}
+
+	metrics.startTarget(a, now.Add(time.Minute))
+	metrics.startFuzzing(a)
+	metrics.progress(a, Progress{Elapsed: 3 * time.Second, Execs: 300, ExecsPerSec: 100, NewInteresting: 1, Total: 3})
+	metrics.finding(a)
+	// fuzzing continued past a suppressed failure adds to the execs
+	metrics.startFuzzing(a)
+	metrics.progress(a, Progress{Elapsed: 3 * time.Second, Execs: 20, ExecsPerSec: 7, Total: 3})
+	// so does a second run of the same target
+	metrics.startTarget(a, now.Add(time.Minute))
+	metrics.startFuzzing(a)
+	metrics.progress(a, Progress{Elapsed: 3 * time.Second, Execs: 50, ExecsPerSec: 17, Total: 3})
+	metrics.startTarget(b, now.Add(30*time.Second))
+	metrics.startFuzzing(b)
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `# HELP go_ci_fuzz_execs_total Executions of the fuzz function.
+# TYPE go_ci_fuzz_execs_total counter
+go_ci_fuzz_execs_total{target="example#FuzzA"} 370
+go_ci_fuzz_execs_total{target="example/\"quoted\"#FuzzB"} 0
+# HELP go_ci_fuzz_execs_per_second Executions per second reported by the last fuzzing status.
+# TYPE go_ci_fuzz_execs_per_second gauge
+go_ci_fuzz_execs_per_second{target="example#FuzzA"} 17
+go_ci_fuzz_execs_per_second{target="example/\"quoted\"#FuzzB"} 0
+# HELP go_ci_fuzz_corpus_size Size of the corpus including seeds reported by the last fuzzing status.
+# TYPE go_ci_fuzz_corpus_size gauge
+go_ci_fuzz_corpus_size{target="example#FuzzA"} 3
+go_ci_fuzz_corpus_size{target="example/\"quoted\"#FuzzB"} 0
+# HELP go_ci_fuzz_new_interesting Inputs added to the cached corpus during the last run of the target.
+# TYPE go_ci_fuzz_new_interesting gauge
+go_ci_fuzz_new_interesting{target="example#FuzzA"} 0
+go_ci_fuzz_new_interesting{target="example/\"quoted\"#FuzzB"} 0
+# HELP go_ci_fuzz_findings_total Failing inputs found.
+# TYPE go_ci_fuzz_findings_total counter
+go_ci_fuzz_findings_total{target="example#FuzzA"} 1
+go_ci_fuzz_findings_total{target="example/\"quoted\"#FuzzB"} 0
+# HELP go_ci_fuzz_current_target 1 for the target being fuzzed, 0 for others.
+# TYPE go_ci_fuzz_current_target gauge
+go_ci_fuzz_current_target{target="example#FuzzA"} 0
+go_ci_fuzz_current_target{target="example/\"quoted\"#FuzzB"} 1
+# HELP go_ci_fuzz_remaining_budget_seconds Time left until the current run is expected to finish.
+# TYPE go_ci_fuzz_remaining_budget_seconds gauge
+go_ci_fuzz_remaining_budget_seconds 30
+`, rec.Body.String())
+
+	metrics.done()
+	rec = httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, rec.Body.String(), `go_ci_fuzz_current_target{target="example/\"quoted\"#FuzzB"} 0`)
+	assert.Contains(t, rec.Body.String(), "go_ci_fuzz_remaining_budget_seconds 0\n")
+}
@@ -0,0 +1,260 @@
+package fuzz
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// outputContextLines is the number of last output lines kept for failure context, e.g. build errors.
+	outputContextLines = 200
+	// maxLineLength truncates longer output lines, e.g. printed values of huge inputs.
+	maxLineLength = 4096
+	// maxRaceReportLines limits race reports which contain stacks of both accesses and of the goroutines' creation.
+	maxRaceReportLines = 200
+
+	raceReportHeader = "WARNING: DATA RACE"
+
+	// failureKilled marks processes killed by SIGKILL, e.g. by the OOM killer or by go-ci-fuzz after an interruption.
+	failureKilled = "killed"
+)
+
+var progressRegex = regexp.MustCompile(`^fuzz: elapsed: (\S+), execs: (\d+) \((\d+)/sec\), new interesting: (\d+) \(total: (\d+)\)`)
+
+// Progress is a status periodically reported by 'go test' while fuzzing, e.g.
+// fuzz: elapsed: 3s, execs: 87532 (29169/sec), new interesting: 0 (total: 1)
+type Progress struct {
+	Elapsed time.Duration
+	Execs   int64
+	// ExecsPerSec is the rate since the previous status.
+	ExecsPerSec int64
+	// NewInteresting is the number of inputs added to the cached corpus during this run.
+	NewInteresting int
+	// Total is the size of the corpus including seeds.
+	Total int
+}
+
+// parseProgress parses a fuzzing status line, false if line is not one.
+func parseProgress(line string) (Progress, bool) {
+	matches := progressRegex.FindStringSubmatch(line)
+	if matches == nil {
+		return Progress{}, false
+	}
+
+	elapsed, err := time.ParseDuration(matches[1])
+	if err != nil {
+		return Progress{}, false
+	}
+	execs, _ := strconv.ParseInt(matches[2], 10, 64)
+	rate, _ := strconv.ParseInt(matches[3], 10, 64)
+	interesting, _ := strconv.Atoi(matches[4])
+	total, _ := strconv.Atoi(matches[5])
+
+	return Progress{
+		Elapsed:        elapsed,
+		Execs:          execs,
+		ExecsPerSec:    rate,
+		NewInteresting: interesting,
+		Total:          total,
+	}, true
+}
+
+// failureTrigger marks the first line of the output describing a failure of the given kind.
+type failureTrigger struct {
+	kind       string
+	substrings []string
+	// terminator ends the excerpt before maxExcerptLines, e.g. the separator closing a race report.
+	terminator string
+	// until ends the excerpt before maxExcerptLines without including the line, it's matched against the line
+	// without leading spaces.
+	until []string
+	// maxLines overrides maxExcerptLines, e.g. for race reports with several stacks.
+	maxLines int
+}
+
+// failureTriggers are ordered by priority, e.g. a worker killed by the OOM killer is also reported as terminated unexpectedly.
+var failureTriggers = []failureTrigger{
+	// workers report only that a race was detected while fuzzing, the report itself is printed when an input is re-run
+	{kind: FailureRace, substrings: []string{raceReportHeader, "race detected during execution of test"}, terminator: "==================",
+		until: []string{"Failing input written to", "FAIL"}, maxLines: maxRaceReportLines},
+	{kind: FailureOOM, substrings: []string{"out of memory"}},
+	{kind: failureKilled, substrings: []string{"signal: killed"}},
+	{kind: FailureHang, substrings: []string{"fuzzing process hung or terminated unexpectedly", "fuzzing process terminated without fuzzing"}},
+	{kind: FailureCrash, substrings: []string{"--- FAIL: "}, until: []string{"Failing input written to", "FAIL"}},
+}
+
+func (t failureTrigger) lines() int {
+	if t.maxLines > 0 {
+		return t.maxLines
+	}
+	return maxExcerptLines
+}
+
+// ends reports whether line ends the excerpt without being part of it.
+func (t failureTrigger) ends(line string) bool {
+	line = strings.TrimLeft(line, " \t")
+	for _, prefix := range t.until {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// outputParser processes output of 'go test' line by line as it arrives. It reports progress, looks for failing inputs
+// and keeps excerpts of known failures and a bounded number of last lines instead of the whole output.
+type outputParser struct {
+	mu         sync.Mutex
+	corpusDir  string
+	onProgress func(Progress)
+
+	input    *FailingInputError
+	inputErr error
+	// excerpts are lines starting with the first trigger of each failure kind
+	excerpts    map[string][]string
+	capturing   map[string]bool
+	buildFailed bool
+	// tail is a ring buffer of the last lines other than progress
+	tail     []string
+	tailNext int
+}
+
+func newOutputParser(corpusDir string, onProgress func(Progress)) *outputParser {
+	return &outputParser{
+		corpusDir:  corpusDir,
+		onProgress: onProgress,
+		excerpts:   map[string][]string{},
+		capturing:  map[string]bool{},
+	}
+}
+
+// stream returns a writer splitting its input into lines fed to the parser.
+// Every output stream needs its own writer so that partial lines of different streams don't mix.
+func (o *outputParser) stream() *lineWriter {
+	return &lineWriter{parser: o}
+}
+
+func (o *outputParser) line(line string) {
+	if len(line) > maxLineLength {
+		line = line[:maxLineLength]
+	}
+	line = strings.TrimSuffix(line, "\r")
+
+	if progress, ok := parseProgress(line); ok {
+		if o.onProgress != nil {
+			o.onProgress(progress)
+		}
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.input == nil && o.inputErr == nil {
+		o.input, o.inputErr = parseFailingInput(o.corpusDir, line)
+	}
+
+	for _, trigger := range failureTriggers {
+		if o.capturing[trigger.kind] {
+			if trigger.ends(line) {
+				o.capturing[trigger.kind] = false
+				continue
+			}
+			o.excerpts[trigger.kind] = append(o.excerpts[trigger.kind], line)
+			if len(o.excerpts[trigger.kind]) >= trigger.lines() || (trigger.terminator != "" && strings.HasPrefix(line, trigger.terminator)) {
+				o.capturing[trigger.kind] = false
+			}
+			continue
+		}
+		if _, seen := o.excerpts[trigger.kind]; seen {
+			continue
+		}
+		for _, substring := range trigger.substrings {
+			if strings.Contains(line, substring) {
+				o.excerpts[trigger.kind] = []string{line}
+				o.capturing[trigger.kind] = true
+				break
+			}
+		}
+	}
+
+	if strings.Contains(line, "[build failed]") || strings.Contains(line, "[setup failed]") {
+		o.buildFailed = true
+	}
+
+	if len(o.tail) < outputContextLines {
+		o.tail = append(o.tail, line)
+	} else {
+		o.tail[o.tailNext] = line
+		o.tailNext = (o.tailNext + 1) % outputContextLines
+	}
+}
+
+// failingInput returns the first failing input found in the output, nil if there is none.
+func (o *outputParser) failingInput() (*FailingInputError, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.input, o.inputErr
+}
+
+// excerpt returns the lines captured for a failure kind, empty if it did not occur.
+func (o *outputParser) excerpt(kind string) string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return strings.TrimSpace(strings.Join(o.excerpts[kind], "\n"))
+}
+
+// lastLines returns up to maxExcerptLines last lines of the output other than progress.
+func (o *outputParser) lastLines() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	lines := append(append([]string{}, o.tail[o.tailNext:]...), o.tail[:o.tailNext]...)
+	if len(lines) > maxExcerptLines {
+		lines = lines[len(lines)-maxExcerptLines:]
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// lineWriter is an io.Writer passing complete lines to an outputParser.
+type lineWriter struct {
+	parser  *outputParser
+	partial []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			// only the beginning of overly long lines is kept
+			if room := maxLineLength - len(w.partial); room > 0 {
+				w.partial = append(w.partial, p[:min(room, len(p))]...)
+			}
+			break
+		}
+
+		line := p[:i]
+		if len(w.partial) > 0 {
+			w.partial = append(w.partial, line[:min(max(maxLineLength-len(w.partial), 0), len(line))]...)
+			line = w.partial
+		}
+		w.parser.line(string(line))
+		w.partial = w.partial[:0]
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+// Close flushes the last line if it's not terminated by a newline.
+func (w *lineWriter) Close() error {
+	if len(w.partial) > 0 {
+		w.parser.line(string(w.partial))
+		w.partial = w.partial[:0]
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package fuzz
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseProgress(t *testing.T) {
+	progress, ok := parseProgress("fuzz: elapsed: 1m3s, execs: 87532 (29169/sec), new interesting: 2 (total: 5)")
+	assert.True(t, ok)
+	assert.Equal(t, Progress{
+		Elapsed:        63 * time.Second,
+		Execs:          87532,
+		ExecsPerSec:    29169,
+		NewInteresting: 2,
+		Total:          5,
+	}, progress)
+
+	for _, line := range []string{
+		"fuzz: elapsed: 0s, gathering baseline coverage: 0/1 completed",
+		"fuzz: elapsed: 0s, gathering baseline coverage: 1/1 completed, now fuzzing with 8 workers",
+		"fuzz: elapsed: 2s, minimizing",
+		"PASS",
+	} {
+		_, ok := parseProgress(line)
+		assert.False(t, ok, line)
+	}
+}
+
+func TestOutputParser(t *testing.T) {
+	t.Run("reports progress and failing input across partial writes", func(t *testing.T) {
+		var progress []Progress
+		output := newOutputParser("testdata/fuzz/FuzzTarget", func(p Progress) {
+			progress = append(progress, p)
+		})
+		stream := output.stream()
+
+		text := "fuzz: elapsed: 3s, execs: 100 (33/sec), new interesting: 1 (total: 2)\n" +
+			"fuzz: elapsed: 6s, execs: 200 (33/sec), new interesting: 1 (total: 2)\n" +
+			"--- FAIL: FuzzTarget (6.01s)\n" +
+			"    go test -run=FuzzTarget/582528ddfad69eb5\n"
+		for i := 0; i < len(text); i += 7 {
+			_, _ = io.WriteString(stream, text[i:min(i+7, len(text))])
+		}
+
+		assert.Len(t, progress, 2)
+		assert.Equal(t, int64(200), progress[1].Execs)
+
+		input, err := output.failingInput()
+		assert.NoError(t, err)
+		assert.Equal(t, &FailingInputError{ID: "582528ddfad69eb5", File: "testdata/fuzz/FuzzTarget/582528ddfad69eb5"}, input)
+		assert.Equal(t, "--- FAIL: FuzzTarget (6.01s)\n    go test -run=FuzzTarget/582528ddfad69eb5", output.lastLines(), "progress must not be kept")
+	})
+
+	t.Run("keeps bounded number of lines", func(t *testing.T) {
+		output := newOutputParser("testdata/fuzz/FuzzTarget", nil)
+		stream := output.stream()
+		for i := 0; i < 10*outputContextLines; i++ {
+			fmt.Fprintf(stream, "line %d\n", i)
+		}
+		_, _ = io.WriteString(stream, strings.Repeat("x", 3*maxLineLength))
+		stream.Close()
+
+		assert.Len(t, output.tail, outputContextLines)
+		lines := strings.Split(output.lastLines(), "\n")
+		assert.Len(t, lines, maxExcerptLines)
+		assert.Equal(t, fmt.Sprintf("line %d", 10*outputContextLines-1), lines[len(lines)-2])
+		assert.Equal(t, strings.Repeat("x", maxLineLength), lines[len(lines)-1], "long lines must be truncated")
+	})
+
+	t.Run("streams don't mix partial lines", func(t *testing.T) {
+		output := newOutputParser("testdata/fuzz/FuzzTarget", nil)
+		stdout, stderr := output.stream(), output.stream()
+
+		_, _ = io.WriteString(stdout, "--- FAIL: ")
+		_, _ = io.WriteString(stderr, "WARNING: DATA RACE\n")
+		_, _ = io.WriteString(stdout, "FuzzTarget\n")
+
+		assert.Equal(t, "WARNING: DATA RACE\n--- FAIL: FuzzTarget", output.lastLines())
+		assert.Equal(t, "WARNING: DATA RACE\n--- FAIL: FuzzTarget", output.excerpt(FailureRace))
+	})
+}
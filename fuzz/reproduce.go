@@ -0,0 +1,104 @@
+package fuzz
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// reproduceTimeout limits a single run of a failing input, e.g. of a hanging one.
+const reproduceTimeout = time.Minute
+
+// Verdicts of a Reproduction.
+const (
+	// Reproducible means that the input failed in all runs.
+	Reproducible = "reproducible"
+	// Flaky means that the input failed in some runs.
+	Flaky = "flaky"
+	// NonReproducible means that the input failed in no run, e.g. because the failure depends on timing.
+	NonReproducible = "non-reproducible"
+)
+
+// Reproduction is the outcome of re-running a failing input.
+type Reproduction struct {
+	Runs     int
+	Failures int
+}
+
+// Verdict returns one of Reproducible, Flaky or NonReproducible.
+func (r Reproduction) Verdict() string {
+	switch r.Failures {
+	case r.Runs:
+		return Reproducible
+	case 0:
+		return NonReproducible
+	default:
+		return Flaky
+	}
+}
+
+func (r Reproduction) String() string {
+	return fmt.Sprintf("%s, failed %d of %d runs", r.Verdict(), r.Failures, r.Runs)
+}
+
+// Reproduce runs the failing input of target runs times with 'go test -run=^FuzzTarget$/^id$' and counts the failures.
+// The input must be in the corpus of the target.
+func (p *Project) Reproduce(ctx context.Context, target Target, input FailingInputError, runs int) (Reproduction, error) {
+	var reproduction Reproduction
+	for i := 0; i < runs; i++ {
+		failed, _, err := p.runInput(ctx, target, input)
+		if err != nil {
+			return reproduction, err
+		}
+		reproduction.Runs++
+		if failed {
+			reproduction.Failures++
+		}
+	}
+	return reproduction, nil
+}
+
+// runInput runs the failing input of target once and returns whether it failed along with the output.
+func (p *Project) runInput(ctx context.Context, target Target, input FailingInputError) (bool, string, error) {
+	args := []string{
+		"test",
+		"-count=1",
+		"-v",
+		"-timeout=" + reproduceTimeout.String(),
+	}
+	args = append(args, p.Build.flags()...)
+	args = append(args, "-run=^"+target.Name+"$/^"+input.ID+"$", target.Package)
+
+	cmd, err := p.goCommand(ctx, args...)
+	if err != nil {
+		return false, "", err
+	}
+	interruptOnCancel(cmd)
+	cmd.WaitDelay = interruptGracePeriod
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err = runCommand(cmd)
+	if ctx.Err() != nil {
+		return false, "", fmt.Errorf("%w: %w", ErrInterrupted, ctx.Err())
+	}
+
+	// the subtest of the input is missing if the input is not in the corpus
+	ran := strings.Contains(output.String(), "=== RUN   "+target.Name+"/"+input.ID+"\n")
+	var exitErr *exec.ExitError
+	switch {
+	case !ran && err == nil:
+		return false, "", fmt.Errorf("failing input %s of %s was not run", input.ID, target)
+	case err == nil:
+		return false, output.String(), nil
+	case ran && errors.As(err, &exitErr):
+		return true, output.String(), nil
+	default:
+		return false, "", fmt.Errorf("running failing input %s of %s failed: %w\n%s", input.ID, target, err, strings.TrimSpace(output.String()))
+	}
+}
@@ -0,0 +1,49 @@
+package fuzz
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+func TestReproduce(t *testing.T) {
+	p := Project{Directory: "./testdata/fuzzing/reproduce", Quiet: true}
+	input := FailingInputError{ID: "crash", File: "testdata/fuzz/FuzzAlways/crash"}
+	target := func(name string) Target {
+		return Target{Name: name, Package: "reproduce", RootPackage: "reproduce"}
+	}
+
+	t.Run("reproducible", func(t *testing.T) {
+		reproduction, err := p.Reproduce(context.Background(), target("FuzzAlways"), input, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, Reproduction{Runs: 2, Failures: 2}, reproduction)
+		assert.Equal(t, Reproducible, reproduction.Verdict())
+	})
+
+	t.Run("flaky", func(t *testing.T) {
+		t.Setenv("REPRODUCE_COUNTER", filepath.Join(t.TempDir(), "counter"))
+		reproduction, err := p.Reproduce(context.Background(), target("FuzzFlaky"), input, 4)
+		assert.NoError(t, err)
+		assert.Equal(t, Reproduction{Runs: 4, Failures: 2}, reproduction)
+		assert.Equal(t, Flaky, reproduction.Verdict())
+	})
+
+	t.Run("non-reproducible", func(t *testing.T) {
+		reproduction, err := p.Reproduce(context.Background(), target("FuzzNever"), input, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, NonReproducible, reproduction.Verdict())
+	})
+
+	t.Run("missing input", func(t *testing.T) {
+		_, err := p.Reproduce(context.Background(), target("FuzzNever"), FailingInputError{ID: "missing"}, 1)
+		assert.ErrorContains(t, err, "was not run")
+	})
+
+	t.Run("interrupted", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := p.Reproduce(ctx, target("FuzzAlways"), input, 1)
+		assert.ErrorIs(t, err, ErrInterrupted)
+	})
+}
@@ -0,0 +1,438 @@
+package fuzz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Exit codes of a run, see RunResult.ExitCode.
+const (
+	ExitOK = 0
+	// ExitError means that the run failed for reasons unrelated to fuzzing, e.g. discovery or build errors.
+	ExitError = 1
+	// ExitFindings means that new failing inputs were found.
+	ExitFindings = 2
+	// ExitSeedRegressions means that only inputs of the seed corpus failed, i.e. a regression of a known input.
+	ExitSeedRegressions = 3
+	// ExitNoTargets means that no targets were discovered and RunOptions.FailOnEmpty is set.
+	ExitNoTargets = 4
+	// ExitInterrupted means that the run was interrupted, e.g. by SIGINT or SIGTERM, following the shell convention of 128+SIGINT.
+	ExitInterrupted = 130
+)
+
+// ErrNoTargets is the error of a run without targets when RunOptions.FailOnEmpty is set.
+var ErrNoTargets = errors.New("no fuzz targets found")
+
+type RunOptions struct {
+	Packages []string
+	// FuzzTime is divided among all targets.
+	FuzzTime time.Duration
+	// Deadline is the wall-clock budget of the whole run including discovery and compilation, it overrides FuzzTime, see Budget.
+	Deadline time.Duration
+	// FailFast stops the run once the first failing input is found.
+	FailFast bool
+	// Out is the directory failing inputs are copied to, using the same structure as corpora in the project.
+	Out string
+	// FailOnEmpty makes a run without targets fail with ErrNoTargets.
+	FailOnEmpty bool
+	// Output receives progress messages, they're discarded if it's nil.
+	Output io.Writer
+	// Metrics records the state of the run if it's not nil.
+	Metrics *Metrics
+	// Findings records failing inputs found if it's not nil, so that known findings can be told apart from new ones.
+	Findings *FindingStore
+	// Suppressions match known failures which are reported as TargetResult.Suppressed and don't affect the exit code.
+	Suppressions Suppressions
+	// ContinueSuppressed continues fuzzing a target for the rest of its time slice after a suppressed failure.
+	// The failing input is removed from the corpus, so only its copy in Out is kept. Failing seeds can't be continued past.
+	ContinueSuppressed bool
+	// Reproduce is the number of times new failing inputs are re-run to tell whether they're reproducible, 0 disables it.
+	Reproduce int
+	// DiscardNonReproducible removes new failing inputs which failed in no re-run from the corpus, they're reported
+	// as TargetResult.Discarded and don't affect the exit code.
+	DiscardNonReproducible bool
+}
+
+// TargetResult is the outcome of fuzzing a single target.
+type TargetResult struct {
+	Target Target
+	// FuzzTime is the time the target was fuzzed for, zero if it was skipped.
+	FuzzTime time.Duration
+	// Elapsed is the wall-clock time including compilation.
+	Elapsed time.Duration
+	// Failure is the failing input found, if any.
+	Failure *FailingInputError
+	// Error is the error returned by Project.Fuzz, e.g. HangError, see FailureKind.
+	Error error
+	// Saved is the path of the copy of the failing input in RunOptions.Out.
+	Saved string
+	// Skipped is set for targets not fuzzed because of the deadline, interruption or RunOptions.FailFast.
+	Skipped bool
+	// Throughput is the progress reported while the target was fuzzed.
+	Throughput Throughput
+	// Finding is the state of the failure recorded in RunOptions.Findings and FindingState one of the Finding* states,
+	// both are empty if there is no failure or no store.
+	Finding      *Finding
+	FindingState string
+	// Suppressed are failures matching RunOptions.Suppressions, they're not Failure.
+	Suppressed []SuppressedFailure
+	// Reproduction is the outcome of re-running Failure or Discarded, nil if it was not re-run.
+	Reproduction *Reproduction
+	// Discarded is the non-reproducible failing input removed from the corpus, see RunOptions.DiscardNonReproducible.
+	Discarded *FailingInputError
+}
+
+// SuppressedFailure is a failing input matching a Suppression.
+type SuppressedFailure struct {
+	Failure     FailingInputError
+	Error       error
+	Suppression Suppression
+	// Saved is the path of the copy of the failing input in RunOptions.Out.
+	Saved string
+}
+
+type RunResult struct {
+	Targets []TargetResult
+	// Err is the error which stopped the run, other than failing inputs.
+	Err         error
+	Interrupted bool
+	Elapsed     time.Duration
+}
+
+// Findings returns results of targets with failing inputs.
+func (r RunResult) Findings() []TargetResult {
+	var findings []TargetResult
+	for _, target := range r.Targets {
+		if target.Failure != nil {
+			findings = append(findings, target)
+		}
+	}
+	return findings
+}
+
+// ExitCode maps the result to one of the Exit* codes. Interruptions take precedence over errors
+// and errors over findings.
+func (r RunResult) ExitCode() int {
+	switch {
+	case r.Interrupted:
+		return ExitInterrupted
+	case errors.Is(r.Err, ErrNoTargets):
+		return ExitNoTargets
+	case r.Err != nil:
+		return ExitError
+	}
+
+	findings := r.Findings()
+	if len(findings) == 0 {
+		return ExitOK
+	}
+	for _, finding := range findings {
+		if !finding.Failure.Seed {
+			return ExitFindings
+		}
+	}
+	return ExitSeedRegressions
+}
+
+// Run discovers targets in packages and fuzzes them one after another.
+func (p *Project) Run(ctx context.Context, opts RunOptions) RunResult {
+	start := time.Now()
+	result := p.run(ctx, opts, start)
+	result.Elapsed = time.Since(start)
+	return result
+}
+
+func (p *Project) run(ctx context.Context, opts RunOptions, start time.Time) RunResult {
+	var result RunResult
+	out := opts.Output
+	if out == nil {
+		out = io.Discard
+	}
+
+	packages := opts.Packages
+	if len(packages) == 0 {
+		packages = []string{"."}
+	}
+
+	// the deadline is a hard stop of everything but the summary, e.g. of builds or re-runs of failing inputs taking
+	// longer than estimated, ctx is still checked to tell interruptions apart
+	runCtx := ctx
+	if opts.Deadline > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithDeadline(ctx, start.Add(opts.Deadline))
+		defer cancel()
+	}
+
+	targets, err := p.ListFuzzTargets(runCtx, packages...)
+	if err != nil {
+		result.Err = err
+		result.Interrupted = ctx.Err() != nil
+		return result
+	}
+
+	if len(targets) == 0 {
+		fmt.Fprintln(out, "No fuzz tests found")
+		if opts.FailOnEmpty {
+			result.Err = ErrNoTargets
+		}
+		return result
+	}
+
+	for _, target := range targets {
+		for _, warning := range target.Warnings {
+			fmt.Fprintf(out, "go-ci-fuzz: warning: %s: %s\n", target, warning)
+		}
+	}
+
+	timePerTarget := time.Duration(opts.FuzzTime.Milliseconds()/int64(len(targets))) * time.Millisecond
+
+	var budget *Budget
+	if opts.Deadline > 0 {
+		budget = NewBudget(start.Add(opts.Deadline), len(targets))
+		fmt.Fprintf(out, "go-ci-fuzz: discovered %d targets, all of them will be fuzzed within %s\n", len(targets), opts.Deadline)
+	} else {
+		fmt.Fprintf(out, "go-ci-fuzz: discovered %d targets, each of them will be fuzzed for %s\n", len(targets), timePerTarget)
+	}
+
+	// statuses are collected per target in addition to calling the Progress callback of the project
+	var throughput *Throughput
+	fuzzer := *p
+	fuzzer.Progress = func(target Target, progress Progress) {
+		throughput.add(progress)
+		if opts.Metrics != nil {
+			opts.Metrics.progress(target, progress)
+		}
+		if p.Progress != nil {
+			p.Progress(target, progress)
+		}
+	}
+
+	if opts.Metrics != nil {
+		defer opts.Metrics.done()
+	}
+
+	// findings are recorded without a commit outside of git repositories
+	commit := ""
+	if opts.Findings != nil {
+		commit, _ = p.head(runCtx)
+	}
+
+	stopped := false
+	for i, target := range targets {
+		targetResult := TargetResult{Target: target, Skipped: true}
+		if !stopped && ctx.Err() == nil && runCtx.Err() != nil {
+			fmt.Fprintf(out, "go-ci-fuzz: deadline of %s reached, skipping %d remaining targets\n", opts.Deadline, len(targets)-i)
+			stopped = true
+		}
+		if stopped || ctx.Err() != nil {
+			result.Targets = append(result.Targets, targetResult)
+			continue
+		}
+		if budget != nil {
+			var ok bool
+			timePerTarget, ok = budget.Next()
+			if !ok {
+				fmt.Fprintf(out, "go-ci-fuzz: deadline of %s reached, skipping %d remaining targets\n", opts.Deadline, budget.Remaining())
+				stopped = true
+				result.Targets = append(result.Targets, targetResult)
+				continue
+			}
+		}
+
+		fmt.Fprintf(out, "go-ci-fuzz: fuzzing %s for %s\n", target, timePerTarget)
+		started := time.Now()
+		if opts.Metrics != nil {
+			budgetEnd := started.Add(time.Duration(len(targets)-i) * timePerTarget)
+			if budget != nil {
+				budgetEnd = budget.Deadline
+			}
+			opts.Metrics.startTarget(target, budgetEnd)
+		}
+		throughput = &targetResult.Throughput
+		err := p.fuzzTarget(runCtx, &fuzzer, target, timePerTarget, opts, commit, &targetResult)
+		targetResult.Skipped = false
+		targetResult.FuzzTime = timePerTarget
+		targetResult.Elapsed = time.Since(started)
+		if budget != nil {
+			budget.Done(timePerTarget, targetResult.Elapsed)
+		}
+
+		switch {
+		case err != nil:
+			result.Err = err
+			stopped = true
+		case targetResult.Failure != nil:
+			stopped = opts.FailFast
+		}
+		result.Targets = append(result.Targets, targetResult)
+	}
+
+	printSummary(out, result)
+
+	if ctx.Err() != nil {
+		result.Interrupted = true
+		fuzzed := 0
+		for _, target := range result.Targets {
+			if !target.Skipped {
+				fuzzed++
+			}
+		}
+		fmt.Fprintf(out, "go-ci-fuzz: interrupted after fuzzing %d of %d targets, found %d failing inputs\n", fuzzed, len(targets), len(result.Findings()))
+	}
+	return result
+}
+
+// fuzzTarget fuzzes target for d and stores the outcome in targetResult. Fuzzing continues past suppressed failures
+// for the rest of d if RunOptions.ContinueSuppressed is set. The returned error stops the run.
+func (p *Project) fuzzTarget(ctx context.Context, fuzzer *Project, target Target, d time.Duration, opts RunOptions, commit string, targetResult *TargetResult) error {
+	out := opts.Output
+	if out == nil {
+		out = io.Discard
+	}
+
+	for {
+		started := time.Now()
+		if opts.Metrics != nil {
+			opts.Metrics.startFuzzing(target)
+		}
+		err := fuzzer.Fuzz(ctx, target, d)
+
+		var inputErr FailingInputError
+		switch {
+		case err == nil, errors.Is(err, ErrInterrupted):
+			return nil
+		case !errors.As(err, &inputErr):
+			targetResult.Error = err
+			return err
+		}
+
+		if opts.Metrics != nil {
+			opts.Metrics.finding(target)
+		}
+		suppression := opts.Suppressions.Match(target, err)
+
+		reproduce := suppression == nil && opts.Reproduce > 0 && !inputErr.Seed && inputErr.File != ""
+		if reproduce && ctx.Err() != nil {
+			// re-runs are bounded by the deadline of the run as well
+			fmt.Fprintf(out, "go-ci-fuzz: %s: not reproducing %s, %s\n", target, inputErr.ID, context.Cause(ctx))
+		} else if reproduce {
+			reproduction, reproduceErr := p.Reproduce(ctx, target, inputErr, opts.Reproduce)
+			switch {
+			case reproduceErr != nil:
+				fmt.Fprintf(out, "go-ci-fuzz: warning: reproducing %s of %s failed: %s\n", inputErr.ID, target, reproduceErr)
+			case reproduction.Verdict() == NonReproducible && opts.DiscardNonReproducible:
+				fmt.Fprintf(out, "go-ci-fuzz: %s: discarding %s, %s\n", target, inputErr, reproduction)
+				targetResult.Reproduction, targetResult.Discarded, targetResult.Error = &reproduction, &inputErr, err
+				if err := os.Remove(filepath.Join(p.Directory, inputErr.File)); err != nil {
+					return fmt.Errorf("cannot remove non-reproducible failing input %s from the corpus: %w", inputErr.File, err)
+				}
+				return nil
+			default:
+				fmt.Fprintf(out, "go-ci-fuzz: %s: %s is %s\n", target, inputErr, reproduction)
+				targetResult.Reproduction = &reproduction
+			}
+		}
+		switch kind := FailureKind(err); {
+		case suppression != nil, kind == FailureCrash:
+		case kind == FailureRace:
+			// unlike other failures, the race report is not part of the output of 'go test' while fuzzing
+			fmt.Fprintf(out, "go-ci-fuzz: %s failed: %s\n", target, err)
+		default:
+			fmt.Fprintf(out, "go-ci-fuzz: %s failed: %s\n", target, firstLine(err.Error()))
+		}
+
+		saved := ""
+		if inputErr.File != "" && opts.Out != "" {
+			var saveErr error
+			if saved, saveErr = p.saveFailingInput(inputErr, opts.Out); saveErr != nil {
+				targetResult.Failure, targetResult.Error = &inputErr, err
+				return saveErr
+			}
+			fmt.Fprintf(out, "Found failing input, saving to %s\n", saved)
+		} else {
+			fmt.Fprintf(out, "Found %s, not saving\n", inputErr)
+		}
+
+		var finding *Finding
+		var findingState string
+		if opts.Findings != nil {
+			input := saved
+			if input == "" && inputErr.File != "" {
+				input = filepath.Join(p.Directory, inputErr.File)
+			}
+			recorded, state, recordErr := opts.Findings.Record(newFinding(target, commit, err, input))
+			if recordErr != nil {
+				targetResult.Failure, targetResult.Error = &inputErr, err
+				return fmt.Errorf("cannot record finding of %s: %w", target, recordErr)
+			}
+			finding, findingState = &recorded, state
+			printFinding(out, recorded, state, inputErr)
+		}
+
+		if suppression == nil {
+			targetResult.Failure, targetResult.Error, targetResult.Saved = &inputErr, err, saved
+			targetResult.Finding, targetResult.FindingState = finding, findingState
+			return nil
+		}
+
+		targetResult.Suppressed = append(targetResult.Suppressed, SuppressedFailure{
+			Failure:     inputErr,
+			Error:       err,
+			Suppression: *suppression,
+			Saved:       saved,
+		})
+		fmt.Fprintf(out, "go-ci-fuzz: %s: suppressed %s finding, %s (%s)\n", target, FailureKind(err), inputErr, suppression)
+
+		d = (d - time.Since(started)).Truncate(time.Millisecond)
+		if !opts.ContinueSuppressed || inputErr.Seed || inputErr.File == "" || d < minFuzzTime || ctx.Err() != nil {
+			return nil
+		}
+		// the target would fail right away on the input left in its corpus
+		if err := os.Remove(filepath.Join(p.Directory, inputErr.File)); err != nil {
+			return fmt.Errorf("cannot remove suppressed failing input %s from the corpus: %w", inputErr.File, err)
+		}
+		fmt.Fprintf(out, "go-ci-fuzz: continuing to fuzz %s for %s\n", target, d)
+	}
+}
+
+// saveFailingInput copies the failing input to the same path relative to out and returns the path of the copy.
+func (p *Project) saveFailingInput(inputErr FailingInputError, out string) (string, error) {
+	srcFile := filepath.Join(p.Directory, inputErr.File)
+	destFile := filepath.Join(out, inputErr.File)
+	destFileFolder := filepath.Dir(destFile)
+
+	if err := os.MkdirAll(destFileFolder, 0755); err != nil {
+		return "", fmt.Errorf("error creating %s directory when copying a failing input from %s: %w", destFileFolder, inputErr.File, err)
+	}
+
+	if err := CopyFile(destFile, srcFile, 0644); err != nil {
+		return "", fmt.Errorf("copying a failing input from %s to %s: %w", srcFile, destFile, err)
+	}
+	return destFile, nil
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// printSummary prints the throughput of fuzzed targets and flags targets with a low exec rate.
+func printSummary(out io.Writer, result RunResult) {
+	for _, target := range result.Targets {
+		if !target.Throughput.Reported() {
+			continue
+		}
+		final := target.Throughput.Final
+		fmt.Fprintf(out, "go-ci-fuzz: %s: %d execs in %s (%.0f/sec), %d new interesting, corpus size %d\n",
+			target.Target, final.Execs, final.Elapsed, target.Throughput.ExecsPerSec(), final.NewInteresting, final.Total)
+		if target.Throughput.Low() {
+			fmt.Fprintf(out, "go-ci-fuzz: warning: %s: low exec rate below %d/sec, is there expensive setup inside the fuzz function?\n", target.Target, LowExecsPerSec)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package fuzz
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRunResultExitCode(t *testing.T) {
+	seed := TargetResult{Failure: &FailingInputError{ID: "seed#0", Seed: true}}
+	found := TargetResult{Failure: &FailingInputError{ID: "0a7e5e215d8c088d", File: "testdata/fuzz/FuzzTarget/0a7e5e215d8c088d"}}
+
+	for name, tt := range map[string]struct {
+		result RunResult
+		code   int
+	}{
+		"no findings":      {RunResult{Targets: []TargetResult{{}}}, ExitOK},
+		"findings":         {RunResult{Targets: []TargetResult{seed, found}}, ExitFindings},
+		"seed regressions": {RunResult{Targets: []TargetResult{seed, {}}}, ExitSeedRegressions},
+		"error":            {RunResult{Targets: []TargetResult{found}, Err: errors.New("build failed")}, ExitError},
+		"no targets":       {RunResult{Err: ErrNoTargets}, ExitNoTargets},
+		"interrupted":      {RunResult{Targets: []TargetResult{found}, Interrupted: true, Err: errors.New("build failed")}, ExitInterrupted},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.code, tt.result.ExitCode())
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	p := Project{Directory: "./testdata/corpus/multiple", Quiet: true}
+	const entry = "0a7e5e215d8c088d4b9c4993d0189a07e81603fbdf64f2ca44738aa27159acef"
+
+	t.Run("saves failing inputs", func(t *testing.T) {
+		out := t.TempDir()
+		result := p.Run(context.Background(), RunOptions{
+			Packages: []string{"..."},
+			FuzzTime: 30 * time.Second,
+			Out:      out,
+		})
+
+		assert.NoError(t, result.Err)
+		assert.Equal(t, ExitSeedRegressions, result.ExitCode())
+		if !assert.Len(t, result.Findings(), 3) {
+			return
+		}
+
+		saved := map[string]string{}
+		for _, finding := range result.Findings() {
+			saved[finding.Target.String()] = finding.Saved
+		}
+		assert.Equal(t, map[string]string{
+			"multiple#FuzzTarget":             filepath.Join(out, "testdata/fuzz/FuzzTarget", entry),
+			"multiple/nocorpus#FuzzSubTarget": "",
+			"multiple/sub#FuzzSubTarget":      filepath.Join(out, "sub/testdata/fuzz/FuzzSubTarget", entry),
+		}, saved)
+
+		files, err := listFilesRecursively(out)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{
+			filepath.Join("testdata/fuzz/FuzzTarget", entry),
+			filepath.Join("sub/testdata/fuzz/FuzzSubTarget", entry),
+		}, files)
+	})
+
+	t.Run("records findings", func(t *testing.T) {
+		store, err := OpenFindingStore(filepath.Join(t.TempDir(), "findings.jsonl"))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		for _, state := range []string{FindingNew, FindingKnown} {
+			result := p.Run(context.Background(), RunOptions{
+				Packages: []string{"..."},
+				FuzzTime: 30 * time.Second,
+				Findings: store,
+			})
+			assert.NoError(t, result.Err)
+			if !assert.Len(t, result.Findings(), 3) {
+				return
+			}
+			for _, finding := range result.Findings() {
+				assert.Equal(t, state, finding.FindingState, finding.Target.String())
+				assert.Equal(t, finding.Target.String(), finding.Finding.Target)
+			}
+		}
+		assert.Len(t, store.Findings(), 3)
+	})
+
+	t.Run("suppressed findings don't affect the exit code", func(t *testing.T) {
+		result := p.Run(context.Background(), RunOptions{
+			Packages:     []string{"..."},
+			FuzzTime:     30 * time.Second,
+			Suppressions: Suppressions{{Target: "FuzzSubTarget"}, {Target: "FuzzTarget"}},
+		})
+		assert.NoError(t, result.Err)
+		assert.Equal(t, ExitOK, result.ExitCode())
+		assert.Empty(t, result.Findings())
+		for _, target := range result.Targets {
+			if assert.Len(t, target.Suppressed, 1, target.Target.String()) {
+				assert.Equal(t, target.Target.Name, target.Suppressed[0].Suppression.Target)
+			}
+		}
+	})
+
+	t.Run("continues past suppressed findings", func(t *testing.T) {
+		dir := t.TempDir()
+		if !assert.NoError(t, copyDirectory(dir, "./testdata/fuzzing/new")) {
+			return
+		}
+		out := t.TempDir()
+
+		p := Project{Directory: dir, Quiet: true}
+		result := p.Run(context.Background(), RunOptions{
+			FuzzTime:           5 * time.Second,
+			Out:                out,
+			Suppressions:       Suppressions{{Target: "FuzzTarget"}},
+			ContinueSuppressed: true,
+		})
+		assert.NoError(t, result.Err)
+		assert.Equal(t, ExitOK, result.ExitCode())
+		if !assert.Len(t, result.Targets, 1) || !assert.NotEmpty(t, result.Targets[0].Suppressed) {
+			return
+		}
+
+		suppressed := result.Targets[0].Suppressed
+		assert.Greater(t, len(suppressed), 1, "fuzzing must continue after the first suppressed failure")
+		for _, failure := range suppressed {
+			assert.False(t, failure.Failure.Seed, "the input must be removed from the corpus before continuing")
+			assert.FileExists(t, failure.Saved)
+		}
+	})
+
+	t.Run("counts execs of continued fuzzing", func(t *testing.T) {
+		dir := t.TempDir()
+		if !assert.NoError(t, copyDirectory(dir, "./testdata/fuzzing/late")) {
+			return
+		}
+
+		// the counter must not go down when fuzzing is continued by a new 'go test' process
+		metrics := NewMetrics()
+		var execs []int64
+		p := Project{Directory: dir, Quiet: true, Progress: func(target Target, _ Progress) {
+			metrics.mu.Lock()
+			defer metrics.mu.Unlock()
+			t := metrics.targets[target.String()]
+			execs = append(execs, t.execsBase+t.progress.Execs)
+		}}
+		result := p.Run(context.Background(), RunOptions{
+			FuzzTime:           10 * time.Second,
+			Suppressions:       Suppressions{{Target: "FuzzTarget"}},
+			ContinueSuppressed: true,
+			Metrics:            metrics,
+		})
+		assert.NoError(t, result.Err)
+		if assert.Len(t, result.Targets, 1) {
+			assert.NotEmpty(t, result.Targets[0].Suppressed, "fuzzing must be continued")
+		}
+		assert.IsNonDecreasing(t, execs, "execs of all runs of the target must be counted")
+	})
+
+	t.Run("discards non-reproducible findings", func(t *testing.T) {
+		dir := t.TempDir()
+		for _, file := range []string{"go.mod", "main_test.go"} {
+			assert.NoError(t, CopyFile(filepath.Join(dir, file), filepath.Join("./testdata/fuzzing/reproduce", file), 0644))
+		}
+
+		p := Project{Directory: dir, Quiet: true}
+		result := p.Run(context.Background(), RunOptions{
+			FuzzTime:               8 * time.Second,
+			Reproduce:              2,
+			DiscardNonReproducible: true,
+		})
+		assert.NoError(t, result.Err)
+		assert.Equal(t, ExitOK, result.ExitCode())
+
+		for _, target := range result.Targets {
+			if target.Target.Name != "FuzzWhileFuzzing" {
+				continue
+			}
+			if assert.NotNil(t, target.Discarded) && assert.NotNil(t, target.Reproduction) {
+				assert.Equal(t, Reproduction{Runs: 2}, *target.Reproduction)
+				assert.NoFileExists(t, filepath.Join(dir, target.Discarded.File))
+			}
+		}
+	})
+
+	t.Run("fail fast skips remaining targets", func(t *testing.T) {
+		result := p.Run(context.Background(), RunOptions{
+			Packages: []string{"..."},
+			FuzzTime: 30 * time.Second,
+			FailFast: true,
+		})
+
+		assert.NoError(t, result.Err)
+		if !assert.Len(t, result.Targets, 3) {
+			return
+		}
+		assert.False(t, result.Targets[0].Skipped)
+		assert.True(t, result.Targets[1].Skipped)
+		assert.True(t, result.Targets[2].Skipped)
+		assert.Len(t, result.Findings(), 1)
+	})
+
+	t.Run("collects throughput", func(t *testing.T) {
+		var reported int
+		p := Project{Directory: "./testdata/fuzzing/nofindings", Quiet: true, Progress: func(Target, Progress) {
+			reported++
+		}}
+
+		result := p.Run(context.Background(), RunOptions{FuzzTime: 4 * time.Second})
+		assert.NoError(t, result.Err)
+		if !assert.Len(t, result.Targets, 1) {
+			return
+		}
+		throughput := result.Targets[0].Throughput
+		assert.True(t, throughput.Reported())
+		assert.Equal(t, reported, len(throughput.Samples), "progress must still be passed to the project callback")
+		assert.Greater(t, throughput.ExecsPerSec(), float64(0))
+	})
+
+	t.Run("deadline is a hard stop", func(t *testing.T) {
+		p := Project{Directory: "./testdata/fuzzing/slow", Quiet: true}
+		result := p.Run(context.Background(), RunOptions{Deadline: 10 * time.Second})
+
+		assert.NoError(t, result.Err)
+		assert.False(t, result.Interrupted, "reaching the deadline is not an interruption")
+		assert.Equal(t, ExitOK, result.ExitCode())
+		assert.Less(t, result.Elapsed, 15*time.Second, "the target must be stopped at the deadline")
+	})
+
+	t.Run("interrupted before fuzzing", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		result := p.Run(ctx, RunOptions{Packages: []string{"..."}, FuzzTime: time.Minute})
+		assert.True(t, result.Interrupted)
+		assert.Equal(t, ExitInterrupted, result.ExitCode())
+	})
+}
@@ -0,0 +1,260 @@
+package fuzz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type ServeOptions struct {
+	Packages []string
+	// FuzzTime is the time every target is fuzzed for before moving on to the next one.
+	FuzzTime time.Duration
+	// Remote and Ref are fetched and checked out every PullInterval, the checkout is left as is if Ref is empty.
+	Remote string
+	Ref    string
+	// PullInterval is how often the project is pulled and checked for new commits.
+	PullInterval time.Duration
+	// StateDir keeps the state surviving restarts:
+	//   - findings.jsonl, the FindingStore
+	//   - inputs, copies of failing inputs using the same structure as corpora in the project
+	//   - cache, copies of the fuzz cache corpus of every target, restored if the Go build cache is cleaned
+	StateDir string
+	// Output receives progress messages, they're discarded if it's nil.
+	Output io.Writer
+	// Metrics records the state of the session if it's not nil.
+	Metrics *Metrics
+	// Suppressions match known failures which are neither recorded nor make the target skipped. The failing input
+	// is removed from the corpus, only its copy in the state directory is kept. Targets with suppressed failing seeds
+	// are skipped until the code changes though.
+	Suppressions Suppressions
+}
+
+// Serve fuzzes targets in packages one after another in rotation until ctx is cancelled. Findings are deduplicated
+// into a FindingStore and targets with a finding or another failure at the checked out commit are skipped until
+// a new commit is checked out, either by pulling ServeOptions.Ref or externally. The project must be a git repository.
+//
+// Failing inputs are kept in the corpus of the project, so a target with an unfixed finding fails again right away
+// once it's fuzzed at a new commit.
+func (p *Project) Serve(ctx context.Context, opts ServeOptions) error {
+	out := opts.Output
+	if out == nil {
+		out = io.Discard
+	}
+	packages := opts.Packages
+	if len(packages) == 0 {
+		packages = []string{"."}
+	}
+
+	store, err := OpenFindingStore(filepath.Join(opts.StateDir, "findings.jsonl"))
+	if err != nil {
+		return err
+	}
+
+	cache, err := p.goEnv(ctx, "GOCACHE")
+	if err != nil {
+		return err
+	}
+	if cache == "off" {
+		fmt.Fprintln(out, "go-ci-fuzz: warning: GOCACHE is off, the fuzz cache corpus is not persisted")
+		cache = ""
+	}
+
+	fuzzer := *p
+	fuzzer.Progress = func(target Target, progress Progress) {
+		if opts.Metrics != nil {
+			opts.Metrics.progress(target, progress)
+		}
+		if p.Progress != nil {
+			p.Progress(target, progress)
+		}
+	}
+	if opts.Metrics != nil {
+		defer opts.Metrics.done()
+	}
+
+	var head string
+	var targets []Target
+	var checked time.Time
+	next := 0
+	// failed are targets which failed without a failing input, e.g. to build, mapped to the commit they failed at
+	failed := map[string]string{}
+	for ctx.Err() == nil {
+		if checked.IsZero() || time.Since(checked) >= opts.PullInterval {
+			checked = time.Now()
+			if opts.Ref != "" {
+				if err := p.pull(ctx, opts.Remote, opts.Ref); err != nil && ctx.Err() == nil {
+					fmt.Fprintf(out, "go-ci-fuzz: warning: pulling %s from %s failed: %s\n", opts.Ref, opts.Remote, err)
+				}
+			}
+
+			commit, err := p.head(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+			if commit != head {
+				head = commit
+				fmt.Fprintf(out, "go-ci-fuzz: discovering targets at %s\n", head)
+				targets, err = p.ListFuzzTargets(ctx, packages...)
+				if err != nil && ctx.Err() == nil {
+					fmt.Fprintf(out, "go-ci-fuzz: warning: discovering targets failed, retrying after the next pull: %s\n", err)
+					head = ""
+				}
+				if len(targets) == 0 && err == nil {
+					fmt.Fprintln(out, "go-ci-fuzz: warning: no fuzz targets found")
+				}
+			}
+		}
+
+		target, ok := nextTarget(targets, &next, func(target Target) bool {
+			return failed[target.String()] == head || store.Unfixed(target, head)
+		})
+		if !ok {
+			// nothing to fuzz until the code changes
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Until(checked.Add(opts.PullInterval))):
+			}
+			continue
+		}
+
+		cacheDir := ""
+		if cache != "" {
+			cacheDir = filepath.Join(cache, "fuzz", filepath.FromSlash(target.Package), target.Name)
+		}
+		savedCacheDir := filepath.Join(opts.StateDir, "cache", filepath.FromSlash(target.Package), target.Name)
+		if cacheDir != "" {
+			if _, err := syncEntries(cacheDir, savedCacheDir); err != nil {
+				fmt.Fprintf(out, "go-ci-fuzz: warning: restoring cache corpus of %s failed: %s\n", target, err)
+			}
+		}
+
+		fmt.Fprintf(out, "go-ci-fuzz: fuzzing %s for %s\n", target, opts.FuzzTime)
+		if opts.Metrics != nil {
+			opts.Metrics.startTarget(target, time.Now().Add(opts.FuzzTime))
+			opts.Metrics.startFuzzing(target)
+		}
+		fuzzErr := fuzzer.Fuzz(ctx, target, opts.FuzzTime)
+
+		if cacheDir != "" {
+			if _, err := syncEntries(savedCacheDir, cacheDir); err != nil {
+				fmt.Fprintf(out, "go-ci-fuzz: warning: persisting cache corpus of %s failed: %s\n", target, err)
+			}
+		}
+
+		var inputErr FailingInputError
+		switch {
+		case fuzzErr == nil, errors.Is(fuzzErr, ErrInterrupted):
+		case errors.As(fuzzErr, &inputErr):
+			if opts.Metrics != nil {
+				opts.Metrics.finding(target)
+			}
+			if suppression := opts.Suppressions.Match(target, fuzzErr); suppression != nil {
+				fmt.Fprintf(out, "go-ci-fuzz: %s: suppressed %s finding, %s (%s)\n", target, FailureKind(fuzzErr), inputErr, suppression)
+				if err := p.dropSuppressed(inputErr, opts); err != nil {
+					fmt.Fprintf(out, "go-ci-fuzz: warning: %s\n", err)
+				}
+				if inputErr.Seed {
+					failed[target.String()] = head
+				}
+				break
+			}
+			if err := p.recordFinding(store, target, head, fuzzErr, opts, out); err != nil {
+				return err
+			}
+		default:
+			if ctx.Err() != nil {
+				break
+			}
+			failed[target.String()] = head
+			fmt.Fprintf(out, "go-ci-fuzz: %s failed, skipping it until the code changes: %s\n", target, firstLine(fuzzErr.Error()))
+		}
+	}
+	return nil
+}
+
+// nextTarget returns the first target starting at *next which is not skipped and advances *next past it.
+func nextTarget(targets []Target, next *int, skip func(Target) bool) (Target, bool) {
+	for i := 0; i < len(targets); i++ {
+		target := targets[(*next+i)%len(targets)]
+		if !skip(target) {
+			*next = (*next + i + 1) % len(targets)
+			return target, true
+		}
+	}
+	return Target{}, false
+}
+
+// recordFinding saves the failing input described by fuzzErr to the state directory and records it in store.
+func (p *Project) recordFinding(store *FindingStore, target Target, commit string, fuzzErr error, opts ServeOptions, out io.Writer) error {
+	var inputErr FailingInputError
+	errors.As(fuzzErr, &inputErr)
+
+	saved := ""
+	if inputErr.File != "" {
+		var err error
+		saved, err = p.saveFailingInput(inputErr, filepath.Join(opts.StateDir, "inputs"))
+		if err != nil {
+			fmt.Fprintf(out, "go-ci-fuzz: warning: %s\n", err)
+		}
+	}
+
+	finding, state, err := store.Record(newFinding(target, commit, fuzzErr, saved))
+	if err != nil {
+		return fmt.Errorf("cannot record finding of %s: %w", target, err)
+	}
+	printFinding(out, finding, state, inputErr)
+	fmt.Fprintf(out, "go-ci-fuzz: skipping %s until the code changes\n", target)
+	return nil
+}
+
+// dropSuppressed moves a new suppressed failing input from the corpus to the state directory, so that fuzzing the target
+// doesn't fail on it right away next time.
+func (p *Project) dropSuppressed(inputErr FailingInputError, opts ServeOptions) error {
+	if inputErr.Seed || inputErr.File == "" {
+		return nil
+	}
+	if _, err := p.saveFailingInput(inputErr, filepath.Join(opts.StateDir, "inputs")); err != nil {
+		return err
+	}
+	return os.Remove(filepath.Join(p.Directory, inputErr.File))
+}
+
+// syncEntries copies regular files of src missing in dest and returns their number, a missing src has no entries.
+func syncEntries(dest, src string) (int, error) {
+	entries, err := os.ReadDir(src)
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	copied := 0
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		destFile := filepath.Join(dest, entry.Name())
+		if _, err := os.Stat(destFile); err == nil {
+			continue
+		} else if !os.IsNotExist(err) {
+			return copied, err
+		}
+
+		if err := os.MkdirAll(dest, 0755); err != nil {
+			return copied, err
+		}
+		if err := CopyFile(destFile, filepath.Join(src, entry.Name()), 0644); err != nil {
+			return copied, err
+		}
+		copied++
+	}
+	return copied, nil
+}
@@ -0,0 +1,155 @@
+package fuzz
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const serveFixture = `package example
+
+import "testing"
+
+func FuzzSeed(f *testing.F) {
+	f.Add("boom")
+	f.Fuzz(func(t *testing.T, s string) {
+		if s == "boom" {
+			panic("seed " + s)
+		}
+	})
+}
+
+func FuzzOK(f *testing.F) {
+	f.Add("a")
+	f.Fuzz(func(t *testing.T, s string) {})
+}
+`
+
+// cancellingWriter collects lines written to it and cancels once stop returns true.
+type cancellingWriter struct {
+	mu     sync.Mutex
+	lines  []string
+	stop   func(lines []string) bool
+	cancel context.CancelFunc
+}
+
+func (w *cancellingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.lines = append(w.lines, strings.Split(strings.TrimSuffix(string(p), "\n"), "\n")...)
+	if w.stop(w.lines) {
+		w.cancel()
+	}
+	return len(p), nil
+}
+
+func countLines(lines []string, substring string) int {
+	count := 0
+	for _, line := range lines {
+		if strings.Contains(line, substring) {
+			count++
+		}
+	}
+	return count
+}
+
+func TestServe(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %s: %s", strings.Join(args, " "), err, out)
+		}
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example\n\ngo 1.21\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "main_test.go"), []byte(serveFixture), 0644))
+	git("init", "--quiet")
+	git("add", ".")
+	git("commit", "--quiet", "-m", "initial")
+
+	p := Project{Directory: dir, Quiet: true}
+	state := t.TempDir()
+	serve := func(fuzzed int) []string {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		out := &cancellingWriter{cancel: cancel, stop: func(lines []string) bool {
+			return countLines(lines, "go-ci-fuzz: fuzzing") > fuzzed
+		}}
+
+		err := p.Serve(ctx, ServeOptions{
+			FuzzTime:     time.Second,
+			PullInterval: time.Hour,
+			StateDir:     state,
+			Output:       out,
+		})
+		assert.NoError(t, err)
+		return out.lines
+	}
+
+	t.Run("skips targets with findings", func(t *testing.T) {
+		lines := serve(3)
+		assert.Equal(t, 1, countLines(lines, "fuzzing example#FuzzSeed"), lines)
+		assert.Equal(t, 1, countLines(lines, "new crash finding"), lines)
+		assert.Equal(t, 1, countLines(lines, "skipping example#FuzzSeed until the code changes"), lines)
+
+		store, err := OpenFindingStore(filepath.Join(state, "findings.jsonl"))
+		if !assert.NoError(t, err) || !assert.Len(t, store.Findings(), 1) {
+			return
+		}
+		finding := store.Findings()[0]
+		assert.Equal(t, "example#FuzzSeed", finding.Target)
+		assert.Equal(t, FailureCrash, finding.Kind)
+		assert.Equal(t, 1, finding.Count)
+		assert.Contains(t, finding.Output, "panic: seed boom")
+	})
+
+	t.Run("still skips after restart", func(t *testing.T) {
+		lines := serve(1)
+		assert.Equal(t, 0, countLines(lines, "fuzzing example#FuzzSeed"), lines)
+	})
+
+	t.Run("fuzzes again once the code changes", func(t *testing.T) {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("changed"), 0644))
+		git("add", ".")
+		git("commit", "--quiet", "-m", "change")
+
+		lines := serve(2)
+		assert.Equal(t, 1, countLines(lines, "known crash finding"), lines)
+
+		store, err := OpenFindingStore(filepath.Join(state, "findings.jsonl"))
+		if assert.NoError(t, err) && assert.Len(t, store.Findings(), 1) {
+			assert.Equal(t, 2, store.Findings()[0].Count)
+		}
+	})
+}
+
+func TestSyncEntries(t *testing.T) {
+	src, dest := t.TempDir(), filepath.Join(t.TempDir(), "dest")
+	assert.NoError(t, os.WriteFile(filepath.Join(src, "a"), []byte("a"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(src, "b"), []byte("b"), 0644))
+
+	copied, err := syncEntries(dest, src)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, copied)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(src, "c"), []byte("c"), 0644))
+	copied, err = syncEntries(dest, src)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, copied)
+
+	copied, err = syncEntries(dest, filepath.Join(src, "missing"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, copied)
+}
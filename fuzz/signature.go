@@ -0,0 +1,79 @@
+package fuzz
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// maxSignatureFrames is the number of innermost stack frames outside of the runtime and testing packages
+// making up a signature.
+const maxSignatureFrames = 3
+
+var (
+	// locationRegex matches the location prefix of messages logged by t.Error, t.Fatal and panics,
+	// e.g. main_test.go:12: too long
+	locationRegex = regexp.MustCompile(`^(\S+\.go):\d+: (.*)$`)
+	quotedRegex   = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+	hexRegex      = regexp.MustCompile(`0x[0-9a-fA-F]+`)
+	numberRegex   = regexp.MustCompile(`\d+`)
+)
+
+// ignoredFrames are prefixes of functions which don't tell failures apart, e.g. the panic machinery.
+var ignoredFrames = []string{"runtime.", "runtime/debug.", "testing.", "reflect.", "panic(", "created by "}
+
+// Signature identifies the failure described by err returned by Project.Fuzz independently of the failing input,
+// line numbers and memory addresses, so that the same bug found by different inputs or at different commits
+// gets the same signature. It's derived from the kind of the failure, the target, the failure message and
+// the innermost stack frames of the fuzzed code.
+func Signature(target Target, err error) string {
+	parts := []string{FailureKind(err), target.String()}
+
+	var location, message string
+	var frames []string
+	for _, line := range strings.Split(FailureOutput(err), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "--- FAIL: ") {
+			continue
+		}
+		if message == "" {
+			message = line
+			if matches := locationRegex.FindStringSubmatch(line); matches != nil {
+				location, message = matches[1], matches[2]
+			}
+			continue
+		}
+		if frame, ok := stackFrame(line); ok && len(frames) < maxSignatureFrames {
+			frames = append(frames, frame)
+		}
+	}
+
+	message = quotedRegex.ReplaceAllString(message, `"_"`)
+	message = hexRegex.ReplaceAllString(message, "0x_")
+	message = numberRegex.ReplaceAllString(message, "N")
+	parts = append(parts, location, message)
+	parts = append(parts, frames...)
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// stackFrame returns the function of a stack trace line without arguments, false if the line is not a function
+// or the function is ignored.
+func stackFrame(line string) (string, bool) {
+	for _, prefix := range ignoredFrames {
+		if strings.HasPrefix(line, prefix) {
+			return "", false
+		}
+	}
+	i := strings.LastIndex(line, "(")
+	if i <= 0 || !strings.HasSuffix(line, ")") {
+		return "", false
+	}
+	function := line[:i]
+	if strings.ContainsAny(function, " :") || !strings.Contains(function, ".") {
+		return "", false
+	}
+	return function, true
+}
@@ -0,0 +1,64 @@
+package fuzz
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSignature(t *testing.T) {
+	target := Target{Name: "FuzzTarget", Package: "example"}
+	input := &FailingInputError{ID: "582528ddfad69eb5", File: "testdata/fuzz/FuzzTarget/582528ddfad69eb5"}
+	panicked := func(goroutine, line, address string) error {
+		return CrashError{Input: input, Output: `--- FAIL: FuzzTarget (0.01s)
+    --- FAIL: FuzzTarget (0.00s)
+        testing.go:2076: panic: runtime error: index out of range [` + line + `] with length 1
+            goroutine ` + goroutine + ` [running]:
+            runtime/debug.Stack()
+            	/usr/local/go/src/runtime/debug/stack.go:26 +0x9b
+            testing.tRunner.func1()
+            	/usr/local/go/src/testing/testing.go:2076 +0x1b0
+            panic({0x83b8b0?, ` + address + `})
+            	/usr/local/go/src/runtime/panic.go:859 +0x125
+            example.parse(...)
+            	/src/example/parse.go:` + line + `
+            example.FuzzTarget.func1(0x0?, {` + address + `, 0x4})
+            	/src/example/main_test.go:28 +0x10e
+            reflect.Value.call({0x826bd8?, 0x8680e8?, 0x13?}, {0x64b39c, 0x4}, {0x87f6d4ab9e0, 0x2, 0x2?})
+            	/usr/local/go/src/reflect/value.go:586 +0xed9`}
+	}
+
+	fatal := func(value string) error {
+		return CrashError{Input: input, Output: `--- FAIL: FuzzTarget (0.01s)
+    --- FAIL: FuzzTarget (0.00s)
+        main_test.go:19: too long: "` + value + `"`}
+	}
+
+	t.Run("ignores inputs, line numbers and addresses", func(t *testing.T) {
+		assert.Equal(t, Signature(target, panicked("19", "12", "0x87f6d45c690?")), Signature(target, panicked("7", "31", "0xc000012345?")))
+		assert.Equal(t, Signature(target, fatal("0000")), Signature(target, fatal("zzzzzzz")))
+	})
+
+	t.Run("tells failures apart", func(t *testing.T) {
+		signatures := map[string]bool{
+			Signature(target, panicked("19", "12", "0x87f6d45c690?")):                                             true,
+			Signature(target, fatal("0000")):                                                                      true,
+			Signature(Target{Name: "FuzzOther", Package: "example"}, fatal("0000")):                               true,
+			Signature(target, RaceError{Input: input, Output: "WARNING: DATA RACE"}):                              true,
+			Signature(target, HangError{Input: input, Output: "fuzzing process hung or terminated unexpectedly"}): true,
+		}
+		assert.Len(t, signatures, 5)
+	})
+
+	t.Run("stack frames", func(t *testing.T) {
+		for line, frame := range map[string]string{
+			"example.FuzzTarget.func1(0x0?, {0x87f6d44a4d1, 0x4})": "example.FuzzTarget.func1",
+			"example.com/a/b.(*Parser).parse(...)":                 "example.com/a/b.(*Parser).parse",
+			"testing.tRunner.func1()":                              "",
+			"/src/example/main_test.go:28 +0x10e":                  "",
+			"goroutine 19 [running]:":                              "",
+		} {
+			actual, _ := stackFrame(line)
+			assert.Equal(t, frame, actual, line)
+		}
+	})
+}
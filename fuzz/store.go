@@ -0,0 +1,106 @@
+package fuzz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// CorpusStore stores corpus entries outside the project, e.g. to share corpora between CI runs and repositories.
+// Entries are addressed by slash separated paths relative to the root of the store using the same structure
+// as corpora are stored in the project, e.g. sub/testdata/fuzz/FuzzTarget/0a7e5e215d8c088d.
+type CorpusStore interface {
+	// List returns names of the entries stored directly in dir, missing directories have no entries.
+	List(ctx context.Context, dir string) ([]string, error)
+	Read(ctx context.Context, name string) ([]byte, error)
+	Write(ctx context.Context, name string, data []byte) error
+	// Close flushes pending writes.
+	Close() error
+}
+
+// OpenCorpusStore opens the corpus store at location which is one of
+//   - s3://bucket/prefix for S3 compatible object stores, see NewS3Store
+//   - file:///path or a plain path of a directory or an archive, see ArchiveStore
+func OpenCorpusStore(ctx context.Context, location string) (CorpusStore, error) {
+	u, err := url.Parse(location)
+	if err != nil || u.Scheme == "" || len(u.Scheme) == 1 {
+		// plain or Windows paths
+		return openLocalStore(location)
+	}
+
+	switch u.Scheme {
+	case "file":
+		p := u.Path
+		if u.Host != "" {
+			return nil, fmt.Errorf("file URLs with a host are not supported: %s", location)
+		}
+		return openLocalStore(filepath.FromSlash(p))
+	case "s3":
+		return NewS3Store(ctx, u)
+	default:
+		return nil, fmt.Errorf("unsupported corpus store %q", location)
+	}
+}
+
+func openLocalStore(p string) (CorpusStore, error) {
+	if IsArchive(p) {
+		return OpenArchiveStore(p)
+	}
+	return NewDirStore(p), nil
+}
+
+// DirStore is a CorpusStore backed by a local directory.
+type DirStore struct {
+	Root string
+}
+
+func NewDirStore(root string) *DirStore {
+	return &DirStore{Root: root}
+}
+
+func (d *DirStore) List(_ context.Context, dir string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(d.Root, filepath.FromSlash(dir)))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+func (d *DirStore) Read(_ context.Context, name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(d.Root, filepath.FromSlash(name)))
+}
+
+func (d *DirStore) Write(_ context.Context, name string, data []byte) error {
+	dest := filepath.Join(d.Root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dest, data, 0644)
+}
+
+func (d *DirStore) Close() error {
+	return nil
+}
+
+// storePath validates and cleans a slash separated path within a store.
+func storePath(name string) (string, error) {
+	cleaned := path.Clean(strings.TrimPrefix(name, "/"))
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", errors.New("path escapes the corpus store: " + name)
+	}
+	return cleaned, nil
+}
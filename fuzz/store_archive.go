@@ -0,0 +1,380 @@
+package fuzz
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/klauspost/compress/zstd"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	compressionNone = "none"
+	compressionGzip = "gzip"
+	compressionZstd = "zstd"
+)
+
+var archiveExtensions = map[string]string{
+	".tar":     compressionNone,
+	".tar.gz":  compressionGzip,
+	".tgz":     compressionGzip,
+	".tar.zst": compressionZstd,
+	".tzst":    compressionZstd,
+}
+
+func archiveCompression(p string) (string, bool) {
+	for ext, compression := range archiveExtensions {
+		if strings.HasSuffix(p, ext) {
+			return compression, true
+		}
+	}
+	return "", false
+}
+
+// IsArchive reports whether p has the extension of an archive supported by ArchiveStore.
+func IsArchive(p string) bool {
+	_, ok := archiveCompression(p)
+	return ok
+}
+
+// manifestName is the name of the manifest within archives, it cannot clash with corpus entries
+// which are always stored in testdata/fuzz/<FuzzTarget> directories.
+const manifestName = "manifest.json"
+
+// ArchiveManifest describes the content of an archive, it's written along with the entries and verified when the archive is opened.
+type ArchiveManifest struct {
+	Targets []ArchiveManifestTarget `json:"targets"`
+}
+
+type ArchiveManifestTarget struct {
+	// Target is the name of the fuzz target, e.g. FuzzTarget.
+	Target string `json:"target"`
+	// Package is the path of the package relative to the archive root, e.g. "." or "sub".
+	Package string `json:"package"`
+	// Directory is the path of the corpus directory in the archive, e.g. sub/testdata/fuzz/FuzzTarget.
+	Directory string `json:"directory"`
+	Entries   int    `json:"entries"`
+	// Checksum is the SHA-256 of the sha256sum(1) style listing of the entries, i.e. "<sha256>  <name>\n" sorted by name.
+	Checksum string `json:"checksum"`
+}
+
+// ArchiveStore is a CorpusStore backed by a tar archive, compressed with gzip if its name ends with .tar.gz or .tgz
+// and with zstd if it ends with .tar.zst or .tzst.
+// The archive is loaded into memory when opened and rewritten on Close if entries have been written.
+type ArchiveStore struct {
+	Path string
+	// Manifest of the opened archive, nil if the archive is new or has no manifest.
+	Manifest    *ArchiveManifest
+	compression string
+	entries     map[string][]byte
+	dirty       bool
+}
+
+func OpenArchiveStore(p string) (*ArchiveStore, error) {
+	compression, ok := archiveCompression(p)
+	if !ok {
+		return nil, fmt.Errorf("unsupported archive %s, expected one of .tar, .tar.gz, .tgz, .tar.zst or .tzst", p)
+	}
+	store := &ArchiveStore{Path: p, compression: compression, entries: map[string][]byte{}}
+
+	f, err := os.Open(p)
+	if os.IsNotExist(err) {
+		return store, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := store.decompress(f)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read archive %s: %w", p, err)
+	}
+	defer r.Close()
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("cannot read archive %s: %w", p, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name, err := storePath(hdr.Name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid archive %s: %w", p, err)
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read %s from archive %s: %w", hdr.Name, p, err)
+		}
+
+		if name == manifestName {
+			store.Manifest = &ArchiveManifest{}
+			if err := json.Unmarshal(data, store.Manifest); err != nil {
+				return nil, fmt.Errorf("invalid manifest in archive %s: %w", p, err)
+			}
+			continue
+		}
+		store.entries[name] = data
+	}
+
+	if store.Manifest != nil {
+		if err := store.verify(); err != nil {
+			return nil, fmt.Errorf("archive %s is corrupted: %w", p, err)
+		}
+	}
+
+	return store, nil
+}
+
+// verify compares entries of the archive with its manifest.
+func (a *ArchiveStore) verify() error {
+	actual := a.manifest()
+	expected := make(map[string]ArchiveManifestTarget, len(a.Manifest.Targets))
+	for _, target := range a.Manifest.Targets {
+		expected[target.Directory] = target
+	}
+
+	for _, target := range actual.Targets {
+		want, ok := expected[target.Directory]
+		if !ok {
+			return fmt.Errorf("%s is not listed in the manifest", target.Directory)
+		}
+		if target.Entries != want.Entries {
+			return fmt.Errorf("%s has %d entries, manifest lists %d", target.Directory, target.Entries, want.Entries)
+		}
+		if target.Checksum != want.Checksum {
+			return fmt.Errorf("checksum of %s does not match the manifest", target.Directory)
+		}
+		delete(expected, target.Directory)
+	}
+
+	if len(expected) > 0 {
+		missing := make([]string, 0, len(expected))
+		for dir := range expected {
+			missing = append(missing, dir)
+		}
+		sort.Strings(missing)
+		return fmt.Errorf("%s listed in the manifest is missing", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// manifest computes the manifest of the current entries.
+func (a *ArchiveStore) manifest() ArchiveManifest {
+	dirs := map[string][]string{}
+	for name := range a.entries {
+		dir := path.Dir(name)
+		dirs[dir] = append(dirs[dir], name)
+	}
+
+	manifest := ArchiveManifest{Targets: []ArchiveManifestTarget{}}
+	for dir, names := range dirs {
+		sort.Strings(names)
+
+		var listing strings.Builder
+		for _, name := range names {
+			listing.WriteString(sha256Hex(a.entries[name]) + "  " + path.Base(name) + "\n")
+		}
+
+		pkg := strings.TrimSuffix(strings.TrimSuffix(dir, path.Base(dir)), "/")
+		pkg = strings.TrimSuffix(strings.TrimSuffix(pkg, "testdata/fuzz"), "/")
+		if pkg == "" {
+			pkg = "."
+		}
+
+		manifest.Targets = append(manifest.Targets, ArchiveManifestTarget{
+			Target:    path.Base(dir),
+			Package:   pkg,
+			Directory: dir,
+			Entries:   len(names),
+			Checksum:  "sha256:" + sha256Hex([]byte(listing.String())),
+		})
+	}
+
+	sort.Slice(manifest.Targets, func(i, j int) bool {
+		return manifest.Targets[i].Directory < manifest.Targets[j].Directory
+	})
+	return manifest
+}
+
+func (a *ArchiveStore) List(_ context.Context, dir string) ([]string, error) {
+	dir, err := storePath(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range a.entries {
+		if path.Dir(name) == dir {
+			names = append(names, path.Base(name))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (a *ArchiveStore) Read(_ context.Context, name string) ([]byte, error) {
+	name, err := storePath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := a.entries[name]
+	if !ok {
+		return nil, fmt.Errorf("%s not found in archive %s: %w", name, a.Path, os.ErrNotExist)
+	}
+	return data, nil
+}
+
+func (a *ArchiveStore) Write(_ context.Context, name string, data []byte) error {
+	name, err := storePath(name)
+	if err != nil {
+		return err
+	}
+
+	if name == manifestName {
+		return fmt.Errorf("%s is reserved for the archive manifest", name)
+	}
+
+	a.entries[name] = data
+	a.dirty = true
+	return nil
+}
+
+// Close writes the archive if it has been modified. The archive is written to a temporary file first
+// so that a failure does not corrupt an existing archive.
+func (a *ArchiveStore) Close() (err error) {
+	if !a.dirty {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(a.Path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(a.Path), filepath.Base(a.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if err := tmp.Chmod(0644); err != nil {
+		return err
+	}
+
+	w, err := a.compress(tmp)
+	if err != nil {
+		return err
+	}
+
+	if err := a.writeTar(w); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), a.Path); err != nil {
+		return err
+	}
+
+	a.dirty = false
+	return nil
+}
+
+func (a *ArchiveStore) writeTar(w io.Writer) error {
+	names := make([]string, 0, len(a.entries))
+	for name := range a.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	manifest, err := json.MarshalIndent(a.manifest(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	modTime := time.Now()
+	tw := tar.NewWriter(w)
+	write := func(name string, data []byte) error {
+		hdr := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(data)),
+			ModTime:  modTime,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	// manifest goes first so that it can be inspected without reading the whole archive
+	if err := write(manifestName, append(manifest, '\n')); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := write(name, a.entries[name]); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+func (a *ArchiveStore) decompress(r io.Reader) (io.ReadCloser, error) {
+	switch a.compression {
+	case compressionGzip:
+		return gzip.NewReader(r)
+	case compressionZstd:
+		decoder, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	default:
+		return io.NopCloser(r), nil
+	}
+}
+
+func (a *ArchiveStore) compress(w io.Writer) (io.WriteCloser, error) {
+	switch a.compression {
+	case compressionGzip:
+		return gzip.NewWriter(w), nil
+	case compressionZstd:
+		return zstd.NewWriter(w)
+	default:
+		return nopWriteCloser{w}, nil
+	}
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,307 @@
+package fuzz
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Store is a CorpusStore backed by an S3 compatible object store, e.g. AWS S3 or MinIO.
+// Entries are stored as objects named <prefix>/<path>.
+type S3Store struct {
+	Bucket string
+	Prefix string
+	// Endpoint is the base URL of the object store, buckets are addressed in path-style, i.e. <endpoint>/<bucket>.
+	Endpoint string
+	Region   string
+
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	Client *http.Client
+	now    func() time.Time
+}
+
+// NewS3Store creates a store for a location in the form of s3://bucket/prefix.
+//
+// Credentials are read from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables,
+// requests are not signed without them. The region is read from AWS_REGION, us-east-1 by default.
+// The endpoint defaults to AWS S3 and can be changed with AWS_ENDPOINT_URL_S3, AWS_ENDPOINT_URL
+// or the endpoint query parameter, e.g. s3://bucket/prefix?endpoint=http://localhost:9000.
+func NewS3Store(_ context.Context, u *url.URL) (*S3Store, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("missing bucket in %s", u)
+	}
+
+	region := u.Query().Get("region")
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	endpoint := u.Query().Get("endpoint")
+	if endpoint == "" {
+		endpoint = os.Getenv("AWS_ENDPOINT_URL_S3")
+	}
+	if endpoint == "" {
+		endpoint = os.Getenv("AWS_ENDPOINT_URL")
+	}
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+
+	return &S3Store{
+		Bucket:          u.Host,
+		Prefix:          strings.Trim(u.Path, "/"),
+		Endpoint:        strings.TrimSuffix(endpoint, "/"),
+		Region:          region,
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		Client:          http.DefaultClient,
+	}, nil
+}
+
+func (s *S3Store) key(name string) (string, error) {
+	name, err := storePath(name)
+	if err != nil {
+		return "", err
+	}
+	if s.Prefix == "" {
+		return name, nil
+	}
+	return s.Prefix + "/" + name, nil
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key string
+	}
+	IsTruncated           bool
+	NextContinuationToken string
+}
+
+func (s *S3Store) List(ctx context.Context, dir string) ([]string, error) {
+	prefix, err := s.key(dir)
+	if err != nil {
+		return nil, err
+	}
+	prefix += "/"
+
+	var names []string
+	token := ""
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", prefix)
+		query.Set("delimiter", "/")
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		body, err := s.do(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result listBucketResult
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("cannot decode object list of s3://%s/%s: %w", s.Bucket, prefix, err)
+		}
+		for _, object := range result.Contents {
+			names = append(names, path.Base(object.Key))
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *S3Store) Read(ctx context.Context, name string) ([]byte, error) {
+	key, err := s.key(name)
+	if err != nil {
+		return nil, err
+	}
+	return s.do(ctx, http.MethodGet, key, nil, nil)
+}
+
+func (s *S3Store) Write(ctx context.Context, name string, data []byte) error {
+	key, err := s.key(name)
+	if err != nil {
+		return err
+	}
+	_, err = s.do(ctx, http.MethodPut, key, nil, data)
+	return err
+}
+
+func (s *S3Store) Close() error {
+	return nil
+}
+
+func (s *S3Store) do(ctx context.Context, method string, key string, query url.Values, payload []byte) ([]byte, error) {
+	u, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", s.Endpoint, err)
+	}
+	u.Path = path.Join("/", u.Path, s.Bucket, key)
+	u.RawPath = ""
+	u.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	if s.AccessKeyID != "" {
+		now := time.Now
+		if s.now != nil {
+			now = s.now
+		}
+		s.sign(req, payload, now())
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound && method == http.MethodGet && key != "" {
+		return nil, fmt.Errorf("s3://%s/%s: %w", s.Bucket, key, os.ErrNotExist)
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("%s s3://%s/%s failed with %s: %s", method, s.Bucket, key, resp.Status, bytes.TrimSpace(body))
+	}
+	return body, nil
+}
+
+// sign adds AWS Signature Version 4 headers to req, see
+// https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html
+func (s *S3Store) sign(req *http.Request, payload []byte, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	headerNames := make([]string, 0, len(headers))
+	for name := range headers {
+		headerNames = append(headerNames, name)
+	}
+	sort.Strings(headerNames)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range headerNames {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(headerNames, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, s.Region, "s3", "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalURI percent-encodes every segment of path, S3 doesn't normalize paths so they are encoded only once.
+func canonicalURI(path string) string {
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = awsEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, awsEscape(key)+"="+awsEscape(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// awsEscape percent-encodes everything but unreserved characters as defined by RFC 3986.
+func awsEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
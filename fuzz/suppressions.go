@@ -0,0 +1,104 @@
+package fuzz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Suppression matches known failures which must not fail runs, e.g. unfixed bugs which would hide new ones.
+// All defined fields must match.
+type Suppression struct {
+	// Target is either the name of the target or the target as formatted by Target.String.
+	Target string `json:"target,omitempty"`
+	// Signature is the signature of the failure or its prefix, see Signature.
+	Signature string `json:"signature,omitempty"`
+	// Message is a regular expression matched against the failure message including the output excerpt, e.g. the panic.
+	Message string `json:"message,omitempty"`
+	// Reason describes why the failure is suppressed, e.g. a link to the issue.
+	Reason string `json:"reason,omitempty"`
+
+	message *regexp.Regexp
+}
+
+func (s Suppression) String() string {
+	var fields []string
+	if s.Target != "" {
+		fields = append(fields, "target "+s.Target)
+	}
+	if s.Signature != "" {
+		fields = append(fields, "signature "+s.Signature)
+	}
+	if s.Message != "" {
+		fields = append(fields, fmt.Sprintf("message %q", s.Message))
+	}
+	description := strings.Join(fields, ", ")
+	if s.Reason != "" {
+		description += ": " + s.Reason
+	}
+	return description
+}
+
+// matches reports whether the failure of target described by err returned by Project.Fuzz is suppressed.
+func (s Suppression) matches(target Target, err error) bool {
+	if s.Target != "" && s.Target != target.Name && s.Target != target.String() {
+		return false
+	}
+	if s.Signature != "" && !strings.HasPrefix(Signature(target, err), s.Signature) {
+		return false
+	}
+	if s.message != nil && !s.message.MatchString(err.Error()) {
+		return false
+	}
+	return true
+}
+
+type Suppressions []Suppression
+
+// LoadSuppressions reads suppressions from a JSON file with an array of Suppression objects, e.g.
+//
+//	[{"target": "FuzzParse", "message": "index out of range", "reason": "#123"}]
+func LoadSuppressions(path string) (Suppressions, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var suppressions Suppressions
+	if err := json.Unmarshal(data, &suppressions); err != nil {
+		return nil, fmt.Errorf("cannot parse suppressions %s: %w", path, err)
+	}
+	for i := range suppressions {
+		if err := suppressions[i].compile(); err != nil {
+			return nil, fmt.Errorf("invalid suppression #%d in %s: %w", i+1, path, err)
+		}
+	}
+	return suppressions, nil
+}
+
+func (s *Suppression) compile() error {
+	if s.Target == "" && s.Signature == "" && s.Message == "" {
+		return errors.New("one of target, signature or message is required")
+	}
+	if s.Message != "" {
+		var err error
+		if s.message, err = regexp.Compile(s.Message); err != nil {
+			return fmt.Errorf("invalid message: %w", err)
+		}
+	}
+	return nil
+}
+
+// Match returns the first suppression matching the failure of target described by err returned by Project.Fuzz,
+// nil if the failure is not suppressed.
+func (s Suppressions) Match(target Target, err error) *Suppression {
+	for i := range s {
+		if s[i].matches(target, err) {
+			return &s[i]
+		}
+	}
+	return nil
+}
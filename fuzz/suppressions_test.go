@@ -0,0 +1,53 @@
+package fuzz
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSuppressions(t *testing.T) {
+	target := Target{Name: "FuzzTarget", Package: "example"}
+	input := &FailingInputError{ID: "582528ddfad69eb5", File: "testdata/fuzz/FuzzTarget/582528ddfad69eb5"}
+	crash := CrashError{Input: input, Output: `--- FAIL: FuzzTarget (0.01s)
+    --- FAIL: FuzzTarget (0.00s)
+        main_test.go:19: index out of range`}
+	signature := Signature(target, crash)
+
+	path := filepath.Join(t.TempDir(), "suppressions.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`[
+  {"target": "FuzzOther"},
+  {"target": "example#FuzzTarget", "message": "index out of range", "reason": "#123"},
+  {"signature": "`+signature[:6]+`"}
+]`), 0644))
+
+	suppressions, err := LoadSuppressions(path)
+	if !assert.NoError(t, err) || !assert.Len(t, suppressions, 3) {
+		return
+	}
+
+	if suppression := suppressions.Match(target, crash); assert.NotNil(t, suppression) {
+		assert.Equal(t, "#123", suppression.Reason)
+		assert.Equal(t, `target example#FuzzTarget, message "index out of range": #123`, suppression.String())
+	}
+	if suppression := suppressions.Match(Target{Name: "FuzzOther", Package: "other"}, crash); assert.NotNil(t, suppression) {
+		assert.Equal(t, "FuzzOther", suppression.Target)
+	}
+	hang := HangError{Input: input, Output: "fuzzing process hung or terminated unexpectedly"}
+	assert.Nil(t, suppressions.Match(target, hang), "all fields of a suppression must match")
+	assert.Nil(t, Suppressions(nil).Match(target, crash))
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, content := range map[string]string{
+			"empty":   `[{"reason": "no matcher"}]`,
+			"regexp":  `[{"message": "("}]`,
+			"no json": `target: FuzzTarget`,
+		} {
+			path := filepath.Join(t.TempDir(), "suppressions.json")
+			assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+			_, err := LoadSuppressions(path)
+			assert.Error(t, err, name)
+		}
+	})
+}
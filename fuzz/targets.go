@@ -4,20 +4,26 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"go/scanner"
+	"go/ast"
+	"go/parser"
 	"go/token"
-	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
+	"strings"
 )
 
 type Target struct {
 	Name        string
 	Package     string
 	RootPackage string
+	// Args are the types of the fuzzing arguments, i.e. parameters of the function passed to f.Fuzz
+	// without the leading *testing.T, nil if they are unknown, see Warnings.
+	Args []string
+	// Seeds is the number of f.Add calls found in the target.
+	Seeds int
+	// Warnings describe problems found during discovery, e.g. missing f.Fuzz call or unsupported argument types.
+	Warnings []string
 }
 
 func (t Target) String() string {
@@ -55,19 +61,17 @@ type Package struct {
 }
 
 func (p *Project) listPackages(ctx context.Context, packages ...string) ([]Package, error) {
-	args := append([]string{
+	args := []string{
 		"list",
 		"-find",
 		"-json",
-	}, packages...)
+	}
+	args = append(args, p.Build.flags()...)
+	args = append(args, packages...)
 
-	goBin, err := exec.LookPath("go")
+	cmd, err := p.goCommand(ctx, args...)
 	if err != nil {
-		return nil, errors.New("go is not installed")
-	}
-	cmd := exec.CommandContext(ctx, goBin, args...)
-	if p.Directory != "" {
-		cmd.Dir = p.Directory
+		return nil, err
 	}
 
 	pkgBytes, err := cmd.Output()
@@ -90,7 +94,7 @@ func (p *Project) listPackages(ctx context.Context, packages ...string) ([]Packa
 }
 
 // We cannot use go test -list because of this bug: https://github.com/golang/go/issues/25339
-// So we list all packages and test files and look for test targets ourselves by running go.Scanner
+// So we list all packages and test files and look for test targets ourselves by parsing them with go/parser
 func (p *Project) listTestTargets(ctx context.Context, pattern string, packages ...string) ([]Target, error) {
 	pkgs, err := p.listPackages(ctx, packages...)
 	if err != nil {
@@ -109,37 +113,100 @@ func (p *Project) listTestTargets(ctx context.Context, pattern string, packages
 		testFiles = append(testFiles, pkg.TestGoFiles...)
 		testFiles = append(testFiles, pkg.XTestGoFiles...)
 
+		// a syntax error only affects its own package, targets of the partially parsed files are still discovered
+		// but warned about, the package fails to build once fuzzed anyway
+		first := len(targets)
+		var parseErrors []string
+		var files []*ast.File
+		// functions passed to f.Fuzz by name are resolved among the functions declared in the test files of the same
+		// package clause, internal and external tests are different packages
+		funcs := map[string]map[string]*ast.FuncType{}
 		for _, testFile := range testFiles {
 			path := filepath.Join(pkg.Dir, testFile)
-			content, err := os.ReadFile(path)
+			fs := token.NewFileSet()
+			f, err := parser.ParseFile(fs, path, nil, parser.SkipObjectResolution)
 			if err != nil {
-				return nil, err
+				parseErrors = append(parseErrors, fmt.Sprintf("cannot parse %s: %s", testFile, err))
+			}
+			if f == nil {
+				continue
 			}
+			files = append(files, f)
 
-			var scan scanner.Scanner
-			fs := token.NewFileSet()
-			f := fs.AddFile(testFile, fs.Base(), len(content))
-			scan.Init(f, content, nil, 0)
-
-			// TODO: we can improve this so that it checks if the first param of the function is testing.F
-			var previousToken token.Token
-			for {
-				_, tok, lit := scan.Scan()
-				if tok == token.EOF {
-					break
+			if funcs[f.Name.Name] == nil {
+				funcs[f.Name.Name] = map[string]*ast.FuncType{}
+			}
+			for _, decl := range f.Decls {
+				if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil {
+					funcs[f.Name.Name][fn.Name.Name] = fn.Type
+				}
+			}
+		}
+
+		for _, f := range files {
+			for _, decl := range f.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Recv != nil || !pat.MatchString(fn.Name.Name) || !isFuzzTarget(fn) {
+					continue
 				}
-				if previousToken == token.FUNC && tok.IsLiteral() {
-					if pat.MatchString(lit) {
-						targets = append(targets, Target{
-							Name:        lit,
-							Package:     pkg.ImportPath,
-							RootPackage: pkg.Module.Path,
-						})
+
+				target := Target{
+					Name:        fn.Name.Name,
+					Package:     pkg.ImportPath,
+					RootPackage: pkg.Module.Path,
+					Seeds:       fuzzSeeds(fn),
+				}
+
+				args, err := fuzzArguments(fn, funcs[f.Name.Name])
+				if err != nil {
+					target.Warnings = append(target.Warnings, err.Error())
+				} else {
+					target.Args = args
+					for _, arg := range args {
+						if !isSupportedArgument(arg) {
+							target.Warnings = append(target.Warnings, fmt.Sprintf("unsupported fuzzing argument type %s", arg))
+						}
 					}
 				}
-				previousToken = tok
+
+				targets = append(targets, target)
 			}
 		}
+		for i := first; i < len(targets) && len(parseErrors) > 0; i++ {
+			targets[i].Warnings = append(append([]string{}, parseErrors...), targets[i].Warnings...)
+		}
 	}
 	return targets, nil
 }
+
+// FindFuzzTarget returns the target described by spec in the form of <package>#<FuzzTarget>
+// as printed by Target.String(). The package is either its import path or its path relative to the project directory.
+func (p *Project) FindFuzzTarget(ctx context.Context, spec string) (Target, error) {
+	pkg, name, ok := strings.Cut(spec, "#")
+	if !ok || name == "" {
+		return Target{}, fmt.Errorf("invalid target %q, expected <package>#<FuzzTarget>", spec)
+	}
+	if pkg == "" {
+		pkg = "."
+	}
+
+	targets, err := p.ListFuzzTargets(ctx, "...")
+	if err != nil {
+		return Target{}, err
+	}
+
+	for _, target := range targets {
+		if target.Name != name {
+			continue
+		}
+		if target.Package == pkg {
+			return target, nil
+		}
+		relPkg, err := filepath.Rel(target.RootPackage, target.Package)
+		if err == nil && relPkg == filepath.Clean(pkg) {
+			return target, nil
+		}
+	}
+
+	return Target{}, fmt.Errorf("fuzz target %q not found", spec)
+}
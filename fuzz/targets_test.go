@@ -16,6 +16,8 @@ func TestDiscoverTargets(t *testing.T) {
 			Name:        "FuzzTarget",
 			Package:     "discover",
 			RootPackage: "discover",
+			Args:        []string{"string"},
+			Seeds:       1,
 		}}, targets)
 	})
 
@@ -27,14 +29,20 @@ func TestDiscoverTargets(t *testing.T) {
 			Name:        "FuzzTarget",
 			Package:     "discover",
 			RootPackage: "discover",
+			Args:        []string{"string"},
+			Seeds:       1,
 		}, {
 			Name:        "FuzzSubTarget",
 			Package:     "discover/subpackage",
 			RootPackage: "discover",
+			Args:        []string{"string"},
+			Seeds:       2,
 		}, {
 			Name:        "FuzzMain",
 			Package:     "discover/submain",
 			RootPackage: "discover",
+			Args:        []string{"string"},
+			Seeds:       2,
 		}}, targets)
 	})
 
@@ -52,6 +60,8 @@ func TestDiscoverTargets(t *testing.T) {
 			Name:        "FuzzSubTarget",
 			Package:     "discover/subpackage",
 			RootPackage: "discover",
+			Args:        []string{"string"},
+			Seeds:       2,
 		}}, targets)
 	})
 
@@ -64,6 +74,146 @@ func TestDiscoverTargets(t *testing.T) {
 			Name:        "FuzzTarget",
 			Package:     "discovermain",
 			RootPackage: "discovermain",
+			Args:        []string{"string"},
+			Seeds:       1,
 		}}, targets)
 	})
 }
+
+func TestDiscoverTaggedTargets(t *testing.T) {
+	untagged := Target{
+		Name:        "FuzzTarget",
+		Package:     "discovertags",
+		RootPackage: "discovertags",
+		Args:        []string{"string"},
+		Seeds:       1,
+	}
+	tagged := Target{
+		Name:        "FuzzIntegration",
+		Package:     "discovertags",
+		RootPackage: "discovertags",
+		Args:        []string{"string"},
+		Seeds:       1,
+	}
+
+	t.Run("without tags", func(t *testing.T) {
+		p := Project{Directory: "./testdata/discovertags", Quiet: true}
+		targets, err := p.ListFuzzTargets(context.Background(), ".")
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []Target{untagged}, targets)
+	})
+
+	t.Run("with tags", func(t *testing.T) {
+		p := Project{Directory: "./testdata/discovertags", Quiet: true, Build: BuildOptions{Tags: []string{"integration"}}}
+		targets, err := p.ListFuzzTargets(context.Background(), ".")
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []Target{untagged, tagged}, targets)
+	})
+
+	t.Run("with GOFLAGS", func(t *testing.T) {
+		p := Project{Directory: "./testdata/discovertags", Quiet: true, Build: BuildOptions{Env: []string{"GOFLAGS=-tags=integration"}}}
+		targets, err := p.ListFuzzTargets(context.Background(), ".")
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []Target{untagged, tagged}, targets)
+	})
+}
+
+func TestDiscoverTargetsWithSyntaxErrors(t *testing.T) {
+	p := Project{Directory: "./testdata/discoverbroken", Quiet: true}
+	targets, err := p.ListFuzzTargets(context.Background(), "...")
+	if !assert.NoError(t, err, "a syntax error must not fail discovery of other packages") || !assert.Len(t, targets, 2) {
+		return
+	}
+
+	for i, target := range targets {
+		if target.Name != "FuzzBroken" {
+			continue
+		}
+		if assert.Len(t, target.Warnings, 1) {
+			assert.Contains(t, target.Warnings[0], "cannot parse broken_test.go: ")
+			assert.Contains(t, target.Warnings[0], "broken_test.go:9:")
+		}
+		targets[i].Warnings = nil
+	}
+	assert.ElementsMatch(t, []Target{{
+		Name:        "FuzzTarget",
+		Package:     "discoverbroken",
+		RootPackage: "discoverbroken",
+		Args:        []string{"string"},
+		Seeds:       1,
+	}, {
+		Name:        "FuzzBroken",
+		Package:     "discoverbroken/broken",
+		RootPackage: "discoverbroken",
+		Args:        []string{"string"},
+	}}, targets, "targets of the partially parsed file must be discovered")
+}
+
+func TestFindFuzzTarget(t *testing.T) {
+	p := Project{Directory: "./testdata/discover", Quiet: true}
+	ctx := context.Background()
+
+	t.Run("by relative package", func(t *testing.T) {
+		target, err := p.FindFuzzTarget(ctx, "subpackage#FuzzSubTarget")
+		assert.NoError(t, err)
+		assert.Equal(t, Target{Name: "FuzzSubTarget", Package: "discover/subpackage", RootPackage: "discover", Args: []string{"string"}, Seeds: 2}, target)
+	})
+
+	t.Run("by import path", func(t *testing.T) {
+		target, err := p.FindFuzzTarget(ctx, "discover#FuzzTarget")
+		assert.NoError(t, err)
+		assert.Equal(t, Target{Name: "FuzzTarget", Package: "discover", RootPackage: "discover", Args: []string{"string"}, Seeds: 1}, target)
+	})
+
+	t.Run("root package", func(t *testing.T) {
+		target, err := p.FindFuzzTarget(ctx, ".#FuzzTarget")
+		assert.NoError(t, err)
+		assert.Equal(t, Target{Name: "FuzzTarget", Package: "discover", RootPackage: "discover", Args: []string{"string"}, Seeds: 1}, target)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := p.FindFuzzTarget(ctx, "subpackage#FuzzTarget")
+		assert.Error(t, err)
+	})
+}
+
+func TestDiscoverFuzzArguments(t *testing.T) {
+	p := Project{Directory: "./testdata/discoverargs", Quiet: true}
+	ctx := context.Background()
+
+	targets, err := p.ListFuzzTargets(ctx, ".")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []Target{{
+		Name:        "FuzzBytes",
+		Package:     "discoverargs",
+		RootPackage: "discoverargs",
+		Args:        []string{"[]byte"},
+	}, {
+		Name:        "FuzzMultiple",
+		Package:     "discoverargs",
+		RootPackage: "discoverargs",
+		Args:        []string{"string", "int", "int", "float64", "bool", "rune"},
+		Seeds:       1,
+	}, {
+		Name:        "FuzzUnsupported",
+		Package:     "discoverargs",
+		RootPackage: "discoverargs",
+		Args:        []string{"string", "custom"},
+		Warnings:    []string{"unsupported fuzzing argument type custom"},
+	}, {
+		Name:        "FuzzNoFuzzCall",
+		Package:     "discoverargs",
+		RootPackage: "discoverargs",
+		Warnings:    []string{"no f.Fuzz(func(t *testing.T, ...)) call found"},
+	}, {
+		Name:        "FuzzNamed",
+		Package:     "discoverargs",
+		RootPackage: "discoverargs",
+		Args:        []string{"string", "uint16"},
+	}, {
+		Name:        "FuzzMethod",
+		Package:     "discoverargs",
+		RootPackage: "discoverargs",
+		Warnings:    []string{"fuzz function is not a literal, arguments unknown"},
+	}}, targets, "FuzzHelper does not accept *testing.F and must not be discovered")
+}
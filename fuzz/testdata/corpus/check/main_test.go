@@ -0,0 +1,13 @@
+package check
+
+import "testing"
+
+func FuzzTarget(f *testing.F) {
+	f.Fuzz(func(t *testing.T, a string, b int) {
+	})
+}
+
+func FuzzAliases(f *testing.F) {
+	f.Fuzz(func(t *testing.T, a []uint8, b rune, c byte) {
+	})
+}
@@ -0,0 +1,10 @@
+package broken
+
+import "testing"
+
+func FuzzBroken(f *testing.F) {
+	f.Fuzz(func(t *testing.T, in string) {})
+}
+
+func broken( {
+}
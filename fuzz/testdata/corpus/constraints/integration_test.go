@@ -0,0 +1,9 @@
+//go:build integration
+
+package constraints
+
+import "testing"
+
+func FuzzIntegration(f *testing.F) {
+	f.Fuzz(func(t *testing.T, in string) {})
+}
@@ -0,0 +1,7 @@
+package constraints
+
+import "testing"
+
+func FuzzTarget(f *testing.F) {
+	f.Fuzz(func(t *testing.T, in string) {})
+}
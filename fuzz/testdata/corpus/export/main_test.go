@@ -0,0 +1,15 @@
+package export
+
+import (
+	"testing"
+)
+
+func FuzzBytes(f *testing.F) {
+	f.Fuzz(func(t *testing.T, in []byte) {
+	})
+}
+
+func FuzzMultiple(f *testing.F) {
+	f.Fuzz(func(t *testing.T, a string, b int) {
+	})
+}
@@ -0,0 +1,3 @@
+module import
+
+go 1.19
@@ -0,0 +1,20 @@
+package importer
+
+import (
+	"testing"
+)
+
+func FuzzBytes(f *testing.F) {
+	f.Fuzz(func(t *testing.T, in []byte) {
+	})
+}
+
+func FuzzString(f *testing.F) {
+	f.Fuzz(func(t *testing.T, in string) {
+	})
+}
+
+func FuzzMultiple(f *testing.F) {
+	f.Fuzz(func(t *testing.T, a string, b int) {
+	})
+}
@@ -0,0 +1,3 @@
+module discoverargs
+
+go 1.19
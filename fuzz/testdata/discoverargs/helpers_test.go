@@ -0,0 +1,11 @@
+package discoverargs
+
+import "testing"
+
+type fuzzer struct{}
+
+func (fuzzer) fuzz(t *testing.T, in []byte) {
+}
+
+func fuzzNamed(t *testing.T, in string, n uint16) {
+}
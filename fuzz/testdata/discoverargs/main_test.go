@@ -0,0 +1,35 @@
+package discoverargs
+
+import "testing"
+
+type custom int
+
+func FuzzBytes(f *testing.F) {
+	f.Fuzz(func(t *testing.T, in []byte) {
+	})
+}
+
+func FuzzMultiple(f *testing.F) {
+	f.Add("a", 1, 2, 1.5, true, 'r')
+	f.Fuzz(func(t *testing.T, a string, b, c int, d float64, e bool, r rune) {
+	})
+}
+
+func FuzzUnsupported(f *testing.F) {
+	f.Fuzz(func(t *testing.T, a string, c custom) {
+	})
+}
+
+func FuzzNoFuzzCall(f *testing.F) {
+}
+
+func FuzzHelper(t *testing.T) {
+}
+
+func FuzzNamed(f *testing.F) {
+	f.Fuzz(fuzzNamed)
+}
+
+func FuzzMethod(f *testing.F) {
+	f.Fuzz(fuzzer{}.fuzz)
+}
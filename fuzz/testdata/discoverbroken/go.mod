@@ -0,0 +1,3 @@
+module discoverbroken
+
+go 1.19
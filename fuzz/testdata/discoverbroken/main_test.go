@@ -0,0 +1,8 @@
+package discoverbroken
+
+import "testing"
+
+func FuzzTarget(f *testing.F) {
+	f.Add("a")
+	f.Fuzz(func(t *testing.T, in string) {})
+}
@@ -0,0 +1,3 @@
+module discovertags
+
+go 1.19
@@ -0,0 +1,17 @@
+//go:build integration
+
+package discovertags
+
+import (
+	"os"
+	"testing"
+)
+
+func FuzzIntegration(f *testing.F) {
+	f.Add("a")
+	f.Fuzz(func(t *testing.T, in string) {
+		if os.Getenv("DISCOVERTAGS_FAIL") != "" {
+			t.Fatal("failing as requested")
+		}
+	})
+}
@@ -0,0 +1,3 @@
+module buildfailure
+
+go 1.19
@@ -0,0 +1,9 @@
+package buildfailure
+
+import "testing"
+
+func FuzzTarget(f *testing.F) {
+	f.Fuzz(func(t *testing.T, in string) {
+		undefined(in)
+	})
+}
@@ -0,0 +1,17 @@
+package late
+
+import (
+	"testing"
+	"time"
+)
+
+var start = time.Now()
+
+// FuzzTarget fails on every input once the process has been fuzzing for a while, i.e. after the first status.
+func FuzzTarget(f *testing.F) {
+	f.Fuzz(func(t *testing.T, s string) {
+		if time.Since(start) > 3500*time.Millisecond && s != "" {
+			t.Fatal("too late")
+		}
+	})
+}
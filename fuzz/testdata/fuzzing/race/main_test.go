@@ -0,0 +1,22 @@
+package race
+
+import "testing"
+
+var counter int
+
+func increment(done chan bool) {
+	counter++
+	done <- true
+}
+
+func FuzzTarget(f *testing.F) {
+	f.Add(1)
+	f.Fuzz(func(t *testing.T, n int) {
+		if n > 100 {
+			done := make(chan bool)
+			go increment(done)
+			counter++
+			<-done
+		}
+	})
+}
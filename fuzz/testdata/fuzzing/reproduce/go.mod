@@ -0,0 +1,3 @@
+module reproduce
+
+go 1.19
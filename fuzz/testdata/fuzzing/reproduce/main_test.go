@@ -0,0 +1,52 @@
+package reproduce
+
+import (
+	"flag"
+	"os"
+	"strconv"
+	"testing"
+)
+
+func FuzzAlways(f *testing.F) {
+	f.Fuzz(func(t *testing.T, s string) {
+		if s == "crash" {
+			t.Fatal("crash")
+		}
+	})
+}
+
+// FuzzFlaky fails every other run, the runs are counted in the file given by REPRODUCE_COUNTER.
+func FuzzFlaky(f *testing.F) {
+	f.Fuzz(func(t *testing.T, s string) {
+		if s == "crash" && flip(t) {
+			t.Fatal("crash")
+		}
+	})
+}
+
+func FuzzNever(f *testing.F) {
+	f.Fuzz(func(t *testing.T, s string) {})
+}
+
+// FuzzWhileFuzzing fails on long inputs only while fuzzing, i.e. not when the input is re-run with -run.
+func FuzzWhileFuzzing(f *testing.F) {
+	f.Add("a")
+	f.Fuzz(func(t *testing.T, s string) {
+		if len(s) > 3 && flag.Lookup("test.fuzz").Value.String() != "" {
+			t.Fatal("too long")
+		}
+	})
+}
+
+func flip(t *testing.T) bool {
+	path := os.Getenv("REPRODUCE_COUNTER")
+	if path == "" {
+		return true
+	}
+	data, _ := os.ReadFile(path)
+	count, _ := strconv.Atoi(string(data))
+	if err := os.WriteFile(path, []byte(strconv.Itoa(count+1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return count%2 == 1
+}
@@ -0,0 +1,17 @@
+package slow
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// TestMain blocks before fuzzing starts, like a slow setup, so -fuzztime doesn't stop it.
+func TestMain(m *testing.M) {
+	time.Sleep(time.Minute)
+	os.Exit(m.Run())
+}
+
+func FuzzSlow(f *testing.F) {
+	f.Fuzz(func(t *testing.T, s string) {})
+}
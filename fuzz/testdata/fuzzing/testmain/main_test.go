@@ -0,0 +1,15 @@
+package testmain
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(3)
+}
+
+func FuzzTarget(f *testing.F) {
+	f.Fuzz(func(t *testing.T, in string) {
+	})
+}
@@ -0,0 +1,61 @@
+package fuzz
+
+const (
+	// LowExecsPerSec is the exec rate below which a target is flagged, native Go fuzzing usually runs thousands of
+	// execs per second, much lower rates point at expensive setup inside the fuzz function.
+	LowExecsPerSec = 100
+	// maxThroughputSamples bounds the time series of long runs, older samples are thinned out once it's reached.
+	maxThroughputSamples = 512
+)
+
+// Throughput is the fuzzing progress of a target, collected from the statuses reported by 'go test'.
+type Throughput struct {
+	// Samples is the time series of statuses, evenly thinned out for long runs.
+	Samples []Progress
+	// Final is the last reported status.
+	Final Progress
+	// stride is the number of statuses represented by each sample.
+	stride  int
+	skipped int
+}
+
+func (t *Throughput) add(progress Progress) {
+	t.Final = progress
+	if t.stride == 0 {
+		t.stride = 1
+	}
+
+	t.skipped++
+	if t.skipped < t.stride {
+		return
+	}
+	t.skipped = 0
+	t.Samples = append(t.Samples, progress)
+
+	if len(t.Samples) >= maxThroughputSamples {
+		thinned := t.Samples[:0]
+		for i := 1; i < len(t.Samples); i += 2 {
+			thinned = append(thinned, t.Samples[i])
+		}
+		t.Samples = thinned
+		t.stride *= 2
+	}
+}
+
+// Reported reports whether any status was reported, i.e. the target was fuzzed for at least a few seconds.
+func (t *Throughput) Reported() bool {
+	return t.Final.Elapsed > 0
+}
+
+// ExecsPerSec returns the mean exec rate of the whole run.
+func (t *Throughput) ExecsPerSec() float64 {
+	if t.Final.Elapsed <= 0 {
+		return 0
+	}
+	return float64(t.Final.Execs) / t.Final.Elapsed.Seconds()
+}
+
+// Low reports whether the mean exec rate is below LowExecsPerSec.
+func (t *Throughput) Low() bool {
+	return t.Reported() && t.ExecsPerSec() < LowExecsPerSec
+}
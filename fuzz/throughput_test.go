@@ -0,0 +1,41 @@
+package fuzz
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestThroughput(t *testing.T) {
+	t.Run("mean rate", func(t *testing.T) {
+		var throughput Throughput
+		assert.False(t, throughput.Reported())
+		assert.False(t, throughput.Low(), "targets without status are not flagged")
+
+		throughput.add(Progress{Elapsed: 3 * time.Second, Execs: 30, ExecsPerSec: 10})
+		throughput.add(Progress{Elapsed: 6 * time.Second, Execs: 600, ExecsPerSec: 190})
+
+		assert.Len(t, throughput.Samples, 2)
+		assert.Equal(t, int64(600), throughput.Final.Execs)
+		assert.Equal(t, float64(100), throughput.ExecsPerSec())
+		assert.False(t, throughput.Low())
+
+		throughput.add(Progress{Elapsed: 9 * time.Second, Execs: 601})
+		assert.True(t, throughput.Low())
+	})
+
+	t.Run("bounded samples", func(t *testing.T) {
+		var throughput Throughput
+		n := 10 * maxThroughputSamples
+		for i := 1; i <= n; i++ {
+			throughput.add(Progress{Elapsed: time.Duration(i) * 3 * time.Second, Execs: int64(i)})
+		}
+
+		assert.Less(t, len(throughput.Samples), maxThroughputSamples)
+		assert.Greater(t, len(throughput.Samples), maxThroughputSamples/4)
+		assert.Equal(t, int64(n), throughput.Final.Execs)
+		for i := 1; i < len(throughput.Samples); i++ {
+			assert.Less(t, throughput.Samples[i-1].Execs, throughput.Samples[i].Execs, "samples must stay ordered")
+		}
+	})
+}
@@ -3,6 +3,7 @@ module github.com/form3tech-oss/go-ci-fuzz
 go 1.21
 
 require (
+	github.com/klauspost/compress v1.17.11
 	github.com/spf13/cobra v1.8.0
 	github.com/stretchr/testify v1.8.4
 )